@@ -22,6 +22,7 @@
 		StatusRequest
 		Status
 		ApplicationHandlerRegistration
+		DownlinkResult
 */
 package broker
 
@@ -94,6 +95,8 @@ type UplinkMessage struct {
 	ProtocolMetadata *protocol.RxMetadata                               `protobuf:"bytes,21,opt,name=protocol_metadata,json=protocolMetadata" json:"protocol_metadata,omitempty"`
 	GatewayMetadata  *gateway.RxMetadata                                `protobuf:"bytes,22,opt,name=gateway_metadata,json=gatewayMetadata" json:"gateway_metadata,omitempty"`
 	DownlinkOptions  []*DownlinkOption                                  `protobuf:"bytes,31,rep,name=downlink_options,json=downlinkOptions" json:"downlink_options,omitempty"`
+	// Set (instead of the other fields) if the message is the result of a downlink
+	DownlinkResult *DownlinkResult `protobuf:"bytes,41,opt,name=downlink_result,json=downlinkResult" json:"downlink_result,omitempty"`
 }
 
 func (m *UplinkMessage) Reset()                    { *m = UplinkMessage{} }
@@ -129,6 +132,13 @@ func (m *UplinkMessage) GetDownlinkOptions() []*DownlinkOption {
 	return nil
 }
 
+func (m *UplinkMessage) GetDownlinkResult() *DownlinkResult {
+	if m != nil {
+		return m.DownlinkResult
+	}
+	return nil
+}
+
 // received from the Handler, sent to the Router, used as Template
 type DownlinkMessage struct {
 	Payload        []byte                                             `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
@@ -138,6 +148,8 @@ type DownlinkMessage struct {
 	AppId          string                                             `protobuf:"bytes,13,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
 	DevId          string                                             `protobuf:"bytes,14,opt,name=dev_id,json=devId,proto3" json:"dev_id,omitempty"`
 	DownlinkOption *DownlinkOption                                    `protobuf:"bytes,21,opt,name=downlink_option,json=downlinkOption" json:"downlink_option,omitempty"`
+	PingSlots      uint32                                             `protobuf:"varint,22,opt,name=ping_slots,json=pingSlots,proto3" json:"ping_slots,omitempty"`
+	TraceId        string                                             `protobuf:"bytes,31,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
 }
 
 func (m *DownlinkMessage) Reset()                    { *m = DownlinkMessage{} }
@@ -486,6 +498,24 @@ func (*ApplicationHandlerRegistration) Descriptor() ([]byte, []int) {
 	return fileDescriptorBroker, []int{12}
 }
 
+// sent to the Broker when the Router scheduled (or failed to schedule) a downlink
+type DownlinkResult struct {
+	TraceId    string `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	AppId      string `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	DevId      string `protobuf:"bytes,3,opt,name=dev_id,json=devId,proto3" json:"dev_id,omitempty"`
+	Accepted   bool   `protobuf:"varint,11,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	GatewayId  string `protobuf:"bytes,12,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`
+	Window     string `protobuf:"bytes,13,opt,name=window,proto3" json:"window,omitempty"`
+	Timestamp  uint32 `protobuf:"varint,14,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Frequency  uint64 `protobuf:"varint,15,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	NackReason string `protobuf:"bytes,21,opt,name=nack_reason,json=nackReason,proto3" json:"nack_reason,omitempty"`
+}
+
+func (m *DownlinkResult) Reset()                    { *m = DownlinkResult{} }
+func (m *DownlinkResult) String() string            { return proto.CompactTextString(m) }
+func (*DownlinkResult) ProtoMessage()               {}
+func (*DownlinkResult) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{13} }
+
 func init() {
 	proto.RegisterType((*DownlinkOption)(nil), "broker.DownlinkOption")
 	proto.RegisterType((*UplinkMessage)(nil), "broker.UplinkMessage")
@@ -500,6 +530,7 @@ func init() {
 	proto.RegisterType((*StatusRequest)(nil), "broker.StatusRequest")
 	proto.RegisterType((*Status)(nil), "broker.Status")
 	proto.RegisterType((*ApplicationHandlerRegistration)(nil), "broker.ApplicationHandlerRegistration")
+	proto.RegisterType((*DownlinkResult)(nil), "broker.DownlinkResult")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1039,6 +1070,18 @@ func (m *UplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.DownlinkResult != nil {
+		dAtA[i] = 0xca
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.DownlinkResult.Size()))
+		n, err := m.DownlinkResult.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
+	}
 	return i, nil
 }
 
@@ -1117,6 +1160,21 @@ func (m *DownlinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n11
 	}
+	if m.PingSlots != 0 {
+		dAtA[i] = 0xb0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.PingSlots))
+	}
+	if len(m.TraceId) > 0 {
+		dAtA[i] = 0xfa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.TraceId)))
+		i += copy(dAtA[i:], m.TraceId)
+	}
 	return i, nil
 }
 
@@ -1782,6 +1840,82 @@ func (m *ApplicationHandlerRegistration) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *DownlinkResult) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DownlinkResult) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TraceId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.TraceId)))
+		i += copy(dAtA[i:], m.TraceId)
+	}
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
+	}
+	if len(m.DevId) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.DevId)))
+		i += copy(dAtA[i:], m.DevId)
+	}
+	if m.Accepted {
+		dAtA[i] = 0x58
+		i++
+		if m.Accepted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.GatewayId) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.GatewayId)))
+		i += copy(dAtA[i:], m.GatewayId)
+	}
+	if len(m.Window) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Window)))
+		i += copy(dAtA[i:], m.Window)
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x70
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Timestamp))
+	}
+	if m.Frequency != 0 {
+		dAtA[i] = 0x78
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Frequency))
+	}
+	if len(m.NackReason) > 0 {
+		dAtA[i] = 0xaa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.NackReason)))
+		i += copy(dAtA[i:], m.NackReason)
+	}
+	return i, nil
+}
+
 func encodeFixed64Broker(dAtA []byte, offset int, v uint64) int {
 	dAtA[offset] = uint8(v)
 	dAtA[offset+1] = uint8(v >> 8)
@@ -1878,6 +2012,10 @@ func (m *UplinkMessage) Size() (n int) {
 			n += 2 + l + sovBroker(uint64(l))
 		}
 	}
+	if m.DownlinkResult != nil {
+		l = m.DownlinkResult.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
 	return n
 }
 
@@ -1912,6 +2050,13 @@ func (m *DownlinkMessage) Size() (n int) {
 		l = m.DownlinkOption.Size()
 		n += 2 + l + sovBroker(uint64(l))
 	}
+	if m.PingSlots != 0 {
+		n += 2 + sovBroker(uint64(m.PingSlots))
+	}
+	l = len(m.TraceId)
+	if l > 0 {
+		n += 2 + l + sovBroker(uint64(l))
+	}
 	return n
 }
 
@@ -2185,6 +2330,45 @@ func (m *ApplicationHandlerRegistration) Size() (n int) {
 	return n
 }
 
+func (m *DownlinkResult) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.TraceId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Accepted {
+		n += 2
+	}
+	l = len(m.GatewayId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.Window)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovBroker(uint64(m.Timestamp))
+	}
+	if m.Frequency != 0 {
+		n += 1 + sovBroker(uint64(m.Frequency))
+	}
+	l = len(m.NackReason)
+	if l > 0 {
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	return n
+}
+
 func sovBroker(x uint64) (n int) {
 	for {
 		n++
@@ -2722,6 +2906,39 @@ func (m *UplinkMessage) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 41:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkResult", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DownlinkResult == nil {
+				m.DownlinkResult = &DownlinkResult{}
+			}
+			if err := m.DownlinkResult.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBroker(dAtA[iNdEx:])
@@ -2991,6 +3208,54 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 22:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PingSlots", wireType)
+			}
+			m.PingSlots = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PingSlots |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBroker(dAtA[iNdEx:])
@@ -5114,6 +5379,288 @@ func (m *ApplicationHandlerRegistration) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *DownlinkResult) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DownlinkResult: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DownlinkResult: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DevId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Accepted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Accepted = bool(v != 0)
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GatewayId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GatewayId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Window = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Frequency", wireType)
+			}
+			m.Frequency = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Frequency |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NackReason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NackReason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipBroker(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -5224,79 +5771,89 @@ func init() {
 }
 
 var fileDescriptorBroker = []byte{
-	// 1178 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xec, 0x58, 0xdf, 0x8e, 0xdb, 0xc4,
-	0x17, 0xfe, 0x79, 0x77, 0x9b, 0xed, 0x9e, 0x34, 0xff, 0xa6, 0xed, 0xae, 0x9b, 0xfe, 0x9a, 0x84,
-	0x20, 0x55, 0x11, 0xa5, 0x49, 0x1b, 0x04, 0x08, 0xa9, 0xa2, 0xca, 0x76, 0x2b, 0x58, 0xa4, 0x94,
-	0xca, 0xdd, 0x72, 0x81, 0x90, 0xa2, 0x89, 0x7d, 0xea, 0x8c, 0xea, 0xd8, 0xae, 0x67, 0x9c, 0x36,
-	0x2f, 0xc0, 0x1b, 0x20, 0x21, 0xee, 0xe8, 0x1b, 0xf0, 0x16, 0x5c, 0x72, 0xcd, 0x05, 0xa0, 0x72,
-	0x81, 0xc4, 0x33, 0x70, 0x81, 0x3c, 0x9e, 0xb1, 0x93, 0xdd, 0xa6, 0x2d, 0x68, 0x25, 0x40, 0xdd,
-	0x2b, 0x7b, 0xbe, 0xf3, 0xcd, 0xe7, 0xe3, 0x73, 0xce, 0x1c, 0xcf, 0x18, 0xde, 0x77, 0x99, 0x98,
-	0xc4, 0xe3, 0xae, 0x1d, 0x4c, 0x7b, 0x07, 0x13, 0x3c, 0x98, 0x30, 0xdf, 0xe5, 0x77, 0x50, 0x3c,
-	0x0e, 0xa2, 0x87, 0x3d, 0x21, 0xfc, 0x1e, 0x0d, 0x59, 0x6f, 0x1c, 0x05, 0x0f, 0x31, 0x52, 0x97,
-	0x6e, 0x18, 0x05, 0x22, 0x20, 0x85, 0x74, 0x54, 0xbf, 0xe8, 0x06, 0x81, 0xeb, 0x61, 0x4f, 0xa2,
-	0xe3, 0xf8, 0x41, 0x0f, 0xa7, 0xa1, 0x98, 0xa7, 0xa4, 0xfa, 0xd5, 0x05, 0x75, 0x37, 0x70, 0x83,
-	0x9c, 0x95, 0x8c, 0xe4, 0x40, 0xde, 0x29, 0x7a, 0x4d, 0x3f, 0x90, 0x86, 0x4c, 0x41, 0x4d, 0x0d,
-	0xc9, 0xa1, 0x1d, 0x78, 0xd9, 0x8d, 0x22, 0x5c, 0xd2, 0x04, 0x97, 0x0a, 0x7c, 0x4c, 0xe7, 0xfa,
-	0x9a, 0x9a, 0xdb, 0x5f, 0xae, 0x41, 0x79, 0x2f, 0x78, 0xec, 0x7b, 0xcc, 0x7f, 0xf8, 0x69, 0x28,
-	0x58, 0xe0, 0x93, 0x06, 0x00, 0x73, 0xd0, 0x17, 0xec, 0x01, 0xc3, 0xc8, 0x34, 0x5a, 0x46, 0x67,
-	0xcb, 0x5a, 0x40, 0xc8, 0x25, 0x00, 0xa5, 0x31, 0x62, 0x8e, 0xb9, 0x26, 0xed, 0x5b, 0x0a, 0xd9,
-	0x77, 0xc8, 0x39, 0x38, 0xc5, 0xed, 0x20, 0x42, 0x73, 0xbd, 0x65, 0x74, 0x4a, 0x56, 0x3a, 0x20,
-	0x75, 0x38, 0xed, 0x20, 0x75, 0x3c, 0xe6, 0xa3, 0xb9, 0xd1, 0x32, 0x3a, 0xeb, 0x56, 0x36, 0x26,
-	0xbb, 0x50, 0xd1, 0x4e, 0x8f, 0xec, 0xc0, 0x7f, 0xc0, 0x5c, 0xf3, 0x54, 0xcb, 0xe8, 0x14, 0xfb,
-	0x17, 0xba, 0xd9, 0xcb, 0x1c, 0x3c, 0xb9, 0x25, 0x2d, 0x71, 0x44, 0x13, 0x27, 0xad, 0xb2, 0xb6,
-	0xa4, 0x30, 0xb9, 0x09, 0x65, 0xed, 0x94, 0x92, 0x28, 0x48, 0x09, 0xb3, 0xab, 0xdf, 0xf7, 0xb0,
-	0x42, 0x49, 0x19, 0x52, 0xb4, 0xfd, 0xfb, 0x3a, 0x94, 0xee, 0x87, 0x49, 0x18, 0x86, 0xc8, 0x39,
-	0x75, 0x91, 0x98, 0xb0, 0x19, 0xd2, 0xb9, 0x17, 0x50, 0x47, 0x06, 0xe1, 0x8c, 0xa5, 0x87, 0xe4,
-	0x0a, 0x6c, 0x4e, 0x53, 0x92, 0x7c, 0xfd, 0x62, 0xbf, 0x96, 0x3b, 0xaa, 0x66, 0x5b, 0x9a, 0x41,
-	0xee, 0xc0, 0xa6, 0x83, 0xb3, 0x11, 0xc6, 0xcc, 0x2c, 0x26, 0x32, 0xbb, 0xef, 0xfe, 0xf8, 0x53,
-	0xf3, 0xfa, 0xcb, 0xca, 0x2a, 0x09, 0x5a, 0x4f, 0xcc, 0x43, 0xe4, 0xdd, 0x3d, 0x9c, 0xdd, 0xbe,
-	0xbf, 0x6f, 0x15, 0x1c, 0x9c, 0xdd, 0x8e, 0x59, 0xa2, 0x47, 0xc3, 0x50, 0xea, 0x9d, 0xf9, 0x5b,
-	0x7a, 0x83, 0x30, 0x94, 0x7a, 0x34, 0x0c, 0x13, 0xbd, 0xf3, 0x90, 0xdc, 0x25, 0xa9, 0x2c, 0xc9,
-	0x54, 0x9e, 0xa2, 0x61, 0xb8, 0xef, 0x24, 0x70, 0xe2, 0x36, 0x73, 0xcc, 0x72, 0x0a, 0x3b, 0x38,
-	0xdb, 0x77, 0xc8, 0x00, 0x6a, 0x59, 0xae, 0xa6, 0x28, 0xa8, 0x43, 0x05, 0x35, 0xcf, 0xcb, 0x20,
-	0x9c, 0xcb, 0x83, 0x60, 0x3d, 0x19, 0x2a, 0x9b, 0x55, 0xd5, 0xa0, 0x46, 0xc8, 0x87, 0x50, 0xd5,
-	0xa9, 0xca, 0x14, 0xb6, 0xa5, 0xc2, 0xd9, 0x2c, 0x59, 0x0b, 0x02, 0x15, 0x85, 0x65, 0xf3, 0x07,
-	0x50, 0x75, 0x54, 0xc5, 0x8e, 0x02, 0x59, 0xb2, 0xdc, 0x6c, 0xb6, 0xd6, 0x3b, 0xc5, 0xfe, 0x76,
-	0x57, 0x2d, 0xc1, 0xe5, 0x8a, 0xb6, 0x2a, 0xce, 0xd2, 0x98, 0xb7, 0x7f, 0x5b, 0x83, 0x8a, 0xe6,
-	0x9c, 0xa4, 0xfb, 0x05, 0xe9, 0xbe, 0x09, 0x95, 0x43, 0xb1, 0x56, 0xc9, 0x5e, 0x15, 0xea, 0xf2,
-	0x72, 0xa8, 0xdb, 0x4f, 0x0d, 0x30, 0xf7, 0x70, 0xc6, 0x6c, 0x1c, 0xd8, 0x82, 0xcd, 0xd2, 0xa5,
-	0x87, 0x3c, 0x0c, 0x7c, 0x7e, 0x6c, 0x21, 0x7f, 0x8e, 0x93, 0xc5, 0xbf, 0xe4, 0xe4, 0x37, 0x1b,
-	0x70, 0x61, 0x0f, 0x9d, 0x38, 0xf4, 0x98, 0x4d, 0x05, 0x3a, 0x27, 0x7d, 0xe0, 0x9f, 0xeb, 0x03,
-	0xeb, 0xaf, 0xdc, 0x07, 0x9a, 0x50, 0xe4, 0x18, 0xcd, 0x30, 0x1a, 0x09, 0x36, 0x45, 0x73, 0x47,
-	0x7e, 0x55, 0x20, 0x85, 0x0e, 0xd8, 0x14, 0xc9, 0x1e, 0xd4, 0x22, 0x55, 0x6a, 0x23, 0x81, 0xd3,
-	0xd0, 0xa3, 0x02, 0xcd, 0xa6, 0xf4, 0x71, 0xe7, 0x70, 0x65, 0xe8, 0x74, 0x55, 0xf5, 0x8c, 0x03,
-	0x35, 0xa1, 0xfd, 0xd5, 0x06, 0xec, 0x1c, 0xad, 0xe0, 0x47, 0x31, 0x72, 0xf1, 0xba, 0x94, 0xc6,
-	0xbf, 0xa0, 0xe9, 0x0f, 0xe1, 0x2c, 0xcd, 0xc2, 0x9f, 0x4b, 0xec, 0x48, 0x89, 0xff, 0xe7, 0x4e,
-	0xe4, 0x39, 0xca, 0xb4, 0x08, 0x3d, 0x82, 0x1d, 0xc7, 0x37, 0xe4, 0x8f, 0x0d, 0x78, 0x73, 0xb1,
-	0x69, 0xbc, 0xe6, 0x35, 0xf2, 0x9f, 0x6b, 0x1f, 0xc7, 0x5c, 0x51, 0x87, 0xba, 0x91, 0x79, 0xa4,
-	0x1b, 0x0d, 0x57, 0x77, 0xa3, 0x56, 0x56, 0x73, 0x2b, 0xbe, 0x94, 0xcf, 0x69, 0x4b, 0xdf, 0xad,
-	0x41, 0x3d, 0x27, 0xde, 0x9a, 0x50, 0xcf, 0x43, 0xdf, 0xc5, 0x93, 0xaa, 0x5b, 0x5d, 0x75, 0x6d,
-	0x07, 0x2e, 0x3e, 0x37, 0x64, 0xc7, 0xba, 0x1d, 0x69, 0x13, 0xa8, 0xde, 0x8b, 0xc7, 0xdc, 0x8e,
-	0xd8, 0x58, 0xa7, 0xa3, 0x5d, 0x81, 0xd2, 0x3d, 0x41, 0x45, 0xcc, 0x35, 0xf0, 0xf3, 0x3a, 0x14,
-	0x52, 0x84, 0x74, 0xa0, 0xc0, 0xe7, 0x5c, 0xe0, 0x54, 0x3e, 0xb5, 0xd8, 0xaf, 0x76, 0x93, 0xe3,
-	0xdd, 0x3d, 0x09, 0x25, 0x14, 0x6e, 0x29, 0x3b, 0xb9, 0x0e, 0x5b, 0x76, 0x30, 0x0d, 0x03, 0x1f,
-	0x7d, 0xa1, 0x1c, 0x39, 0x2b, 0xc9, 0xb7, 0x34, 0x9a, 0xf2, 0x73, 0x16, 0x69, 0x43, 0x21, 0x96,
-	0xbb, 0x19, 0xb5, 0x25, 0x02, 0xc9, 0xb7, 0xa8, 0x40, 0x6e, 0x29, 0x0b, 0xe9, 0x41, 0x29, 0xbd,
-	0x1b, 0xc5, 0x3e, 0x7b, 0x14, 0xa3, 0x4c, 0xcd, 0x32, 0xf5, 0x4c, 0x4a, 0xb8, 0x2f, 0xed, 0xe4,
-	0x32, 0x9c, 0xd6, 0xdd, 0x50, 0xc6, 0x7d, 0x99, 0x9b, 0xd9, 0xc8, 0xdb, 0x50, 0xcc, 0x57, 0x0a,
-	0x97, 0xb9, 0x58, 0xa6, 0x2e, 0x9a, 0xc9, 0x07, 0xb0, 0xb0, 0xae, 0xb8, 0xf6, 0xa5, 0x72, 0x64,
-	0x52, 0x6d, 0x81, 0xa5, 0x1c, 0x7a, 0x0f, 0x4a, 0x4e, 0xd6, 0x8a, 0x93, 0xfd, 0x5f, 0x75, 0x21,
-	0x92, 0x77, 0x31, 0xb2, 0x93, 0xc3, 0xab, 0x87, 0xdc, 0x5a, 0xa6, 0x91, 0x2b, 0x50, 0xb3, 0x03,
-	0xdf, 0x47, 0x5b, 0xa0, 0x33, 0x8a, 0x82, 0x58, 0x60, 0xc4, 0x65, 0x1b, 0x2a, 0x59, 0xd5, 0xcc,
-	0x60, 0xa5, 0x38, 0xb9, 0x0a, 0x24, 0x27, 0x4f, 0xa8, 0xef, 0x78, 0x09, 0x7b, 0x5b, 0xb2, 0x73,
-	0x99, 0x8f, 0x95, 0xa1, 0xfd, 0x19, 0x34, 0x06, 0x61, 0xf6, 0x28, 0x05, 0x5b, 0xe8, 0x32, 0x2e,
-	0xd2, 0x13, 0xe8, 0x42, 0xf1, 0x1a, 0x8b, 0xc5, 0x7b, 0x09, 0x40, 0xa9, 0x2f, 0x9c, 0xaf, 0x15,
-	0xb2, 0xef, 0xf4, 0x9f, 0xae, 0x41, 0x61, 0x57, 0xb6, 0x0b, 0x72, 0x13, 0xb6, 0x06, 0x9c, 0x07,
-	0x36, 0xa3, 0x02, 0xc9, 0x79, 0xdd, 0x44, 0x96, 0x76, 0xaf, 0xf5, 0x55, 0x3b, 0x9d, 0x8e, 0x71,
-	0xcd, 0x20, 0x9f, 0xc0, 0x56, 0x56, 0xaa, 0xc4, 0xd4, 0xcc, 0xc3, 0xd5, 0x5b, 0x7f, 0x23, 0xef,
-	0x4f, 0x2b, 0x36, 0xc9, 0xd7, 0x0c, 0x72, 0x03, 0x36, 0xef, 0xc6, 0x63, 0x8f, 0xf1, 0x09, 0x59,
-	0xf5, 0xcc, 0xfa, 0x76, 0x37, 0xfd, 0x1b, 0xd2, 0xd5, 0xff, 0x39, 0xba, 0xb7, 0xa7, 0xa1, 0x98,
-	0x77, 0x0c, 0x32, 0x84, 0xd3, 0x6a, 0x69, 0x22, 0x69, 0xae, 0x6e, 0x87, 0xa9, 0x3f, 0x2f, 0xed,
-	0x97, 0xfd, 0x6f, 0x0d, 0x28, 0xa5, 0x41, 0x1a, 0x52, 0x9f, 0xba, 0x18, 0x91, 0x2f, 0xa0, 0x9e,
-	0x06, 0x1f, 0xa3, 0xa3, 0x69, 0x21, 0x97, 0xb5, 0xe2, 0x8b, 0x53, 0xb6, 0xea, 0x05, 0x48, 0x1f,
-	0xb6, 0x3e, 0x42, 0xa1, 0x16, 0x74, 0x96, 0x89, 0xa5, 0x25, 0x5f, 0x2f, 0x2f, 0xc3, 0xbb, 0x37,
-	0xbe, 0x7f, 0xd6, 0x30, 0x7e, 0x78, 0xd6, 0x30, 0x7e, 0x79, 0xd6, 0x30, 0xbe, 0xfe, 0xb5, 0xf1,
-	0xbf, 0xcf, 0xdf, 0x7a, 0xf5, 0x9f, 0x4d, 0xe3, 0x82, 0xf4, 0xe0, 0x9d, 0x3f, 0x03, 0x00, 0x00,
-	0xff, 0xff, 0x1f, 0x69, 0x15, 0xd0, 0xa1, 0x12, 0x00, 0x00,
+	// 1331 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xec, 0x58, 0x4f, 0x6f, 0x1b, 0x45,
+	0x14, 0x67, 0x93, 0xd4, 0x89, 0x9f, 0x63, 0xc7, 0x99, 0x36, 0xc9, 0xd6, 0x6d, 0x13, 0x63, 0xa4,
+	0xca, 0x50, 0x6a, 0xb7, 0x46, 0x80, 0x90, 0x2a, 0xaa, 0xa4, 0xa9, 0x20, 0x48, 0x29, 0xd5, 0x26,
+	0xe5, 0x80, 0x90, 0xac, 0xf1, 0xce, 0x8b, 0x33, 0xea, 0x7a, 0x77, 0xbb, 0x33, 0xeb, 0x34, 0x5f,
+	0x00, 0x89, 0x0f, 0x80, 0x84, 0xb8, 0xb5, 0x57, 0x4e, 0x7c, 0x0b, 0x8e, 0x9c, 0x39, 0x00, 0x2a,
+	0x5f, 0x83, 0x03, 0x9a, 0xd9, 0xd9, 0xf5, 0x3a, 0x8e, 0xdb, 0x82, 0x22, 0x01, 0x6a, 0x4f, 0xf6,
+	0xfb, 0xbd, 0xdf, 0xbe, 0xf9, 0xf3, 0x7e, 0xf3, 0xe6, 0x0f, 0x7c, 0xd8, 0xe7, 0xf2, 0x30, 0xee,
+	0xb5, 0xdc, 0x60, 0xd0, 0xde, 0x3f, 0xc4, 0xfd, 0x43, 0xee, 0xf7, 0xc5, 0x3d, 0x94, 0x47, 0x41,
+	0xf4, 0xb0, 0x2d, 0xa5, 0xdf, 0xa6, 0x21, 0x6f, 0xf7, 0xa2, 0xe0, 0x21, 0x46, 0xe6, 0xa7, 0x15,
+	0x46, 0x81, 0x0c, 0x48, 0x21, 0xb1, 0x6a, 0x97, 0xfa, 0x41, 0xd0, 0xf7, 0xb0, 0xad, 0xd1, 0x5e,
+	0x7c, 0xd0, 0xc6, 0x41, 0x28, 0x8f, 0x13, 0x52, 0xed, 0x7a, 0x2e, 0x7a, 0x3f, 0xe8, 0x07, 0x23,
+	0x96, 0xb2, 0xb4, 0xa1, 0xff, 0x19, 0xfa, 0x72, 0xda, 0x20, 0x0d, 0xb9, 0x81, 0x36, 0x52, 0x48,
+	0x9b, 0x6e, 0xe0, 0x65, 0x7f, 0x0c, 0xe1, 0x4a, 0x4a, 0xe8, 0x53, 0x89, 0x47, 0xf4, 0x38, 0xfd,
+	0x4d, 0xdc, 0x8d, 0xaf, 0x67, 0xa0, 0xb2, 0x1d, 0x1c, 0xf9, 0x1e, 0xf7, 0x1f, 0x7e, 0x1e, 0x4a,
+	0x1e, 0xf8, 0x64, 0x1d, 0x80, 0x33, 0xf4, 0x25, 0x3f, 0xe0, 0x18, 0xd9, 0x56, 0xdd, 0x6a, 0x16,
+	0x9d, 0x1c, 0x42, 0xae, 0x00, 0x98, 0x18, 0x5d, 0xce, 0xec, 0x19, 0xed, 0x2f, 0x1a, 0x64, 0x87,
+	0x91, 0x0b, 0x70, 0x4e, 0xb8, 0x41, 0x84, 0xf6, 0x6c, 0xdd, 0x6a, 0x96, 0x9d, 0xc4, 0x20, 0x35,
+	0x58, 0x60, 0x48, 0x99, 0xc7, 0x7d, 0xb4, 0xe7, 0xea, 0x56, 0x73, 0xd6, 0xc9, 0x6c, 0xb2, 0x05,
+	0x4b, 0x69, 0xa7, 0xbb, 0x6e, 0xe0, 0x1f, 0xf0, 0xbe, 0x7d, 0xae, 0x6e, 0x35, 0x4b, 0x9d, 0x8b,
+	0xad, 0x6c, 0x30, 0xfb, 0x8f, 0xef, 0x68, 0x4f, 0x1c, 0x51, 0xd5, 0x49, 0xa7, 0x92, 0x7a, 0x12,
+	0x98, 0xdc, 0x86, 0x4a, 0xda, 0x29, 0x13, 0xa2, 0xa0, 0x43, 0xd8, 0xad, 0x74, 0xbc, 0x27, 0x23,
+	0x94, 0x8d, 0x23, 0x41, 0x1b, 0x3f, 0xcc, 0x41, 0xf9, 0x41, 0xa8, 0xa6, 0x61, 0x17, 0x85, 0xa0,
+	0x7d, 0x24, 0x36, 0xcc, 0x87, 0xf4, 0xd8, 0x0b, 0x28, 0xd3, 0x93, 0xb0, 0xe8, 0xa4, 0x26, 0xb9,
+	0x06, 0xf3, 0x83, 0x84, 0xa4, 0x87, 0x5f, 0xea, 0x2c, 0x8f, 0x3a, 0x6a, 0xbe, 0x76, 0x52, 0x06,
+	0xb9, 0x07, 0xf3, 0x0c, 0x87, 0x5d, 0x8c, 0xb9, 0x5d, 0x52, 0x61, 0xb6, 0xde, 0xff, 0xe5, 0xd7,
+	0x8d, 0x9b, 0x2f, 0x92, 0x95, 0x9a, 0xb4, 0xb6, 0x3c, 0x0e, 0x51, 0xb4, 0xb6, 0x71, 0x78, 0xf7,
+	0xc1, 0x8e, 0x53, 0x60, 0x38, 0xbc, 0x1b, 0x73, 0x15, 0x8f, 0x86, 0xa1, 0x8e, 0xb7, 0xf8, 0x8f,
+	0xe2, 0x6d, 0x86, 0xa1, 0x8e, 0x47, 0xc3, 0x50, 0xc5, 0x5b, 0x01, 0xf5, 0x4f, 0xa5, 0xb2, 0xac,
+	0x53, 0x79, 0x8e, 0x86, 0xe1, 0x0e, 0x53, 0xb0, 0xea, 0x36, 0x67, 0x76, 0x25, 0x81, 0x19, 0x0e,
+	0x77, 0x18, 0xd9, 0x84, 0xe5, 0x2c, 0x57, 0x03, 0x94, 0x94, 0x51, 0x49, 0xed, 0x15, 0x3d, 0x09,
+	0x17, 0x46, 0x93, 0xe0, 0x3c, 0xde, 0x35, 0x3e, 0xa7, 0x9a, 0x82, 0x29, 0x42, 0x3e, 0x86, 0x6a,
+	0x9a, 0xaa, 0x2c, 0xc2, 0xaa, 0x8e, 0x70, 0x3e, 0x4b, 0x56, 0x2e, 0xc0, 0x92, 0xc1, 0xb2, 0xef,
+	0x37, 0xa1, 0xca, 0x8c, 0x62, 0xbb, 0x81, 0x96, 0xac, 0xb0, 0x37, 0xea, 0xb3, 0xcd, 0x52, 0x67,
+	0xb5, 0x65, 0x96, 0xe0, 0xb8, 0xa2, 0x9d, 0x25, 0x36, 0x66, 0x0b, 0x72, 0x1b, 0x32, 0xa8, 0x1b,
+	0xa1, 0x88, 0x3d, 0x69, 0xbf, 0x5d, 0xb7, 0x4e, 0x8b, 0xe0, 0x68, 0xaf, 0x53, 0x61, 0x63, 0x76,
+	0xe3, 0xc9, 0x2c, 0x2c, 0xa5, 0x94, 0xd7, 0x7a, 0x79, 0x8e, 0x5e, 0xf2, 0x33, 0x9d, 0x24, 0xcb,
+	0x5e, 0x39, 0x7d, 0xa6, 0x4d, 0xae, 0x2a, 0xe3, 0xb9, 0x52, 0xd5, 0x26, 0xe4, 0x7e, 0xbf, 0x2b,
+	0xbc, 0x40, 0x0a, 0xad, 0x93, 0xb2, 0x53, 0x54, 0xc8, 0x9e, 0x02, 0xc8, 0x45, 0x58, 0x90, 0x11,
+	0x75, 0x51, 0x35, 0xbc, 0xa1, 0x1b, 0x9e, 0xd7, 0xf6, 0x0e, 0x6b, 0x3c, 0xb5, 0xc0, 0xde, 0xc6,
+	0x21, 0x77, 0x71, 0xd3, 0x95, 0x7c, 0x98, 0xac, 0x7a, 0x14, 0x61, 0xe0, 0x8b, 0x33, 0x4b, 0xd6,
+	0x29, 0xc3, 0x2b, 0xfd, 0x9d, 0xe1, 0x35, 0xbe, 0x9f, 0x83, 0x8b, 0xdb, 0xc8, 0xe2, 0xd0, 0xe3,
+	0x2e, 0x95, 0xc8, 0x5e, 0x97, 0xa0, 0x7f, 0xaf, 0x04, 0xcd, 0xbe, 0x74, 0x09, 0xda, 0x80, 0x92,
+	0xc0, 0x68, 0x88, 0x51, 0x57, 0xf2, 0x01, 0xda, 0x6b, 0x7a, 0x43, 0x83, 0x04, 0xda, 0xe7, 0x03,
+	0x24, 0xdb, 0xb0, 0x1c, 0x19, 0xa9, 0x75, 0x25, 0x0e, 0x42, 0x8f, 0x4a, 0xd4, 0xfa, 0x2c, 0x75,
+	0xd6, 0x4e, 0x2a, 0x23, 0x4d, 0x57, 0x35, 0xfd, 0x62, 0xdf, 0x7c, 0xd0, 0xf8, 0x76, 0x0e, 0xd6,
+	0x26, 0x15, 0xfc, 0x28, 0x46, 0x21, 0x5f, 0x15, 0x69, 0xfc, 0x07, 0xf6, 0x9b, 0x5d, 0x38, 0x4f,
+	0xb3, 0xe9, 0x1f, 0x85, 0x58, 0xd3, 0x21, 0x2e, 0x8f, 0x3a, 0x31, 0xca, 0x51, 0x16, 0x8b, 0xd0,
+	0x09, 0xec, 0x0c, 0xb6, 0xaf, 0xc6, 0x9f, 0x73, 0xf0, 0x56, 0xbe, 0x68, 0xbc, 0xe2, 0x1a, 0xf9,
+	0xdf, 0x95, 0x8f, 0x33, 0x56, 0xd4, 0x89, 0x6a, 0x64, 0x4f, 0x54, 0xa3, 0xdd, 0xe9, 0xd5, 0xa8,
+	0x9e, 0x69, 0x6e, 0xca, 0x4e, 0x79, 0x4a, 0x59, 0xfa, 0x71, 0x06, 0x6a, 0x23, 0xe2, 0x9d, 0x43,
+	0xea, 0x79, 0xe8, 0xf7, 0xf1, 0xb5, 0xea, 0xa6, 0xab, 0xae, 0xc1, 0xe0, 0xd2, 0xa9, 0x53, 0x76,
+	0xa6, 0xc7, 0x91, 0x06, 0x81, 0xea, 0x5e, 0xdc, 0x13, 0x6e, 0xc4, 0x7b, 0x69, 0x3a, 0x1a, 0x4b,
+	0x50, 0xde, 0x93, 0x54, 0xc6, 0x22, 0x05, 0x7e, 0x9b, 0x85, 0x42, 0x82, 0x90, 0x26, 0x14, 0xc4,
+	0xb1, 0x90, 0x38, 0xd0, 0xad, 0x96, 0x3a, 0xd5, 0x96, 0xba, 0x59, 0xee, 0x69, 0x48, 0x51, 0x84,
+	0x63, 0xfc, 0xe4, 0x26, 0x14, 0xdd, 0x60, 0x10, 0x06, 0x3e, 0xfa, 0xd2, 0x74, 0xe4, 0xbc, 0x26,
+	0xdf, 0x49, 0xd1, 0x84, 0x3f, 0x62, 0x91, 0x06, 0x14, 0x62, 0x7d, 0x9a, 0x31, 0x47, 0x22, 0xd0,
+	0x7c, 0x87, 0x4a, 0x14, 0x8e, 0xf1, 0x90, 0x36, 0x94, 0x93, 0x7f, 0xdd, 0xd8, 0xe7, 0x8f, 0x62,
+	0xb4, 0x17, 0x27, 0xa8, 0x8b, 0x09, 0xe1, 0x81, 0xf6, 0x93, 0xab, 0xb0, 0x90, 0x56, 0x43, 0xbb,
+	0x3c, 0xc1, 0xcd, 0x7c, 0xe4, 0x5d, 0x28, 0x8d, 0x56, 0x8a, 0xb0, 0x2b, 0x13, 0xd4, 0xbc, 0x9b,
+	0x7c, 0x04, 0xb9, 0x75, 0x25, 0xd2, 0xbe, 0x2c, 0x4d, 0x7c, 0xb4, 0x9c, 0x63, 0x99, 0x0e, 0x7d,
+	0x00, 0x65, 0x96, 0x95, 0x62, 0x75, 0xfe, 0xab, 0xe6, 0x66, 0xf2, 0x3e, 0x46, 0xae, 0xba, 0x37,
+	0x7b, 0x28, 0x9c, 0x71, 0x1a, 0xb9, 0x06, 0xcb, 0x6e, 0xe0, 0xfb, 0xe8, 0x4a, 0x64, 0xdd, 0x28,
+	0x88, 0x25, 0x46, 0x42, 0x97, 0xa1, 0xb2, 0x53, 0xcd, 0x1c, 0x4e, 0x82, 0x93, 0xeb, 0x40, 0x46,
+	0xe4, 0x43, 0xea, 0x33, 0x4f, 0xb1, 0x93, 0xc3, 0xf0, 0x28, 0xcc, 0xa7, 0xc6, 0xd1, 0xf8, 0x02,
+	0xd6, 0x37, 0xc3, 0xac, 0x29, 0x03, 0x3b, 0xd8, 0xe7, 0x42, 0x26, 0x97, 0xdf, 0x9c, 0x78, 0xad,
+	0xbc, 0x78, 0xaf, 0x00, 0x98, 0xe8, 0xb9, 0xab, 0xbd, 0x41, 0x76, 0x58, 0xe3, 0x9b, 0xdc, 0x63,
+	0x41, 0x72, 0x11, 0x1a, 0x3b, 0x7f, 0x5b, 0x63, 0xe7, 0xef, 0x5c, 0x1b, 0x33, 0xa7, 0x2f, 0x90,
+	0xd9, 0x7c, 0x59, 0xae, 0xc1, 0x02, 0x75, 0x5d, 0x0c, 0x25, 0x32, 0xad, 0x97, 0x05, 0x27, 0xb3,
+	0x4f, 0xbc, 0x38, 0x2c, 0x9e, 0x7c, 0x71, 0x58, 0x85, 0xc2, 0x11, 0xf7, 0x59, 0x70, 0x64, 0x56,
+	0xa2, 0xb1, 0xc8, 0x65, 0x28, 0xaa, 0x82, 0x28, 0x24, 0x1d, 0x84, 0x5a, 0x01, 0x65, 0x67, 0x04,
+	0x28, 0xef, 0x41, 0xa4, 0x96, 0x84, 0xef, 0x1e, 0xeb, 0x54, 0xcf, 0x39, 0x23, 0x40, 0xd5, 0x54,
+	0x9f, 0xba, 0xea, 0x76, 0x48, 0x85, 0xb9, 0xb3, 0x14, 0x1d, 0x50, 0x90, 0xa3, 0x91, 0xce, 0xd3,
+	0x19, 0x28, 0x6c, 0xe9, 0xd2, 0x49, 0x6e, 0x43, 0x71, 0x53, 0x88, 0xc0, 0xe5, 0x54, 0x22, 0x59,
+	0x49, 0x0b, 0xea, 0xd8, 0x49, 0xbe, 0x36, 0xed, 0xd4, 0xd7, 0xb4, 0x6e, 0x58, 0xe4, 0x33, 0x28,
+	0x66, 0xcb, 0x96, 0xd8, 0x29, 0xf3, 0xe4, 0x4a, 0xae, 0xbd, 0x99, 0xc5, 0x98, 0x76, 0x61, 0xb8,
+	0x61, 0x91, 0x5b, 0x30, 0x7f, 0x3f, 0xee, 0x79, 0x5c, 0x1c, 0x92, 0x69, 0x6d, 0xd6, 0x56, 0x5b,
+	0xc9, 0xa3, 0x54, 0x2b, 0x7d, 0x6e, 0x6a, 0xdd, 0x55, 0x8f, 0x52, 0x4d, 0x8b, 0xec, 0xc2, 0x82,
+	0x29, 0x53, 0x48, 0x36, 0xa6, 0x6f, 0x0d, 0x49, 0x7f, 0x5e, 0xb8, 0x77, 0x74, 0x9e, 0x58, 0x50,
+	0x4e, 0x26, 0x69, 0x97, 0xfa, 0xb4, 0x8f, 0x11, 0xf9, 0x0a, 0x6a, 0x89, 0x10, 0x31, 0x9a, 0x94,
+	0x28, 0xb9, 0x9a, 0x46, 0x7c, 0xbe, 0x7c, 0xa7, 0x0d, 0x80, 0x74, 0xa0, 0xf8, 0x09, 0x4a, 0x53,
+	0xdc, 0xb2, 0x4c, 0x8c, 0x95, 0xbf, 0x5a, 0x65, 0x1c, 0xde, 0xba, 0xf5, 0xd3, 0xb3, 0x75, 0xeb,
+	0xe7, 0x67, 0xeb, 0xd6, 0xef, 0xcf, 0xd6, 0xad, 0xef, 0xfe, 0x58, 0x7f, 0xe3, 0xcb, 0x77, 0x5e,
+	0xfe, 0xcd, 0xaf, 0x57, 0xd0, 0x3d, 0x78, 0xef, 0xaf, 0x01, 0x00, 0xa1, 0xe8, 0x63, 0x7e, 0x28,
+	0x14, 0x00, 0x00,
 }
@@ -41,6 +41,9 @@ type RouterStream interface {
 	Stream
 	Send(*UplinkMessage) error
 	Channel() <-chan *DownlinkMessage
+	// DownlinkOptionsSort returns the order of downlink options that the
+	// broker requested, or an empty string if it did not request an order
+	DownlinkOptionsSort() string
 }
 
 // NewMonitoredRouterStream starts and monitors a RouterStream
@@ -78,6 +81,17 @@ func NewMonitoredRouterStream(client BrokerClient, getContextFunc func() context
 
 			s.ctx.Debug("Started Associate stream")
 
+			// Receive the order of downlink options that the broker requests
+			go func() {
+				md, err := client.Header()
+				if err != nil {
+					return
+				}
+				if sort := md[DownlinkOptionsSortKey]; len(sort) > 0 {
+					s.setDownlinkOptionsSort(sort[0])
+				}
+			}()
+
 			// Receive downlink errors
 			go func() {
 				for {
@@ -153,6 +167,9 @@ type routerStream struct {
 	up     chan *UplinkMessage
 	down   chan *DownlinkMessage
 	err    chan error
+
+	sortLock sync.RWMutex
+	sort     string
 }
 
 func (s *routerStream) Send(uplink *UplinkMessage) error {
@@ -168,6 +185,18 @@ func (s *routerStream) Channel() <-chan *DownlinkMessage {
 	return s.down
 }
 
+func (s *routerStream) DownlinkOptionsSort() string {
+	s.sortLock.RLock()
+	defer s.sortLock.RUnlock()
+	return s.sort
+}
+
+func (s *routerStream) setDownlinkOptionsSort(sort string) {
+	s.sortLock.Lock()
+	defer s.sortLock.Unlock()
+	s.sort = sort
+}
+
 func (s *routerStream) Close() {
 	s.closing = true
 	close(s.up)
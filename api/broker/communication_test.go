@@ -155,6 +155,7 @@ func TestRouterBrokerCommunication(t *testing.T) {
 	conn, _ := api.Dial(fmt.Sprintf("localhost:%d", port))
 
 	{
+		brk.DownlinkOptionsSort = "score"
 		brk.RouterAssociateChanFunc = func(md metadata.MD) (chan *UplinkMessage, <-chan *DownlinkMessage, func(), error) {
 			up := make(chan *UplinkMessage, 1)
 			down := make(chan *DownlinkMessage, 1)
@@ -215,6 +216,9 @@ func TestRouterBrokerCommunication(t *testing.T) {
 
 		time.Sleep(10 * time.Millisecond)
 
+		// The broker requested the order of downlink options
+		a.So(stream.DownlinkOptionsSort(), ShouldEqual, "score")
+
 		stream.Close()
 
 		time.Sleep(10 * time.Millisecond)
@@ -10,15 +10,25 @@ import (
 	"github.com/TheThingsNetwork/ttn/api"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
+// DownlinkOptionsSortKey is the key of the header of Associate streams in
+// which the broker requests the order of the downlink options of uplink
+const DownlinkOptionsSortKey = "downlink-options-sort"
+
 // BrokerStreamServer handles gRPC streams as channels
 type BrokerStreamServer struct {
 	ctx                      log.Interface
 	RouterAssociateChanFunc  func(md metadata.MD) (up chan *UplinkMessage, down <-chan *DownlinkMessage, cancel func(), err error)
 	HandlerSubscribeChanFunc func(md metadata.MD) (ch <-chan *DeduplicatedUplinkMessage, cancel func(), err error)
 	HandlerPublishChanFunc   func(md metadata.MD) (ch chan *DownlinkMessage, err error)
+
+	// DownlinkOptionsSort is the order in which routers should send the
+	// downlink options of uplink (for example score). If it is empty, routers
+	// use their default order.
+	DownlinkOptionsSort string
 }
 
 // NewBrokerStreamServer returns a new BrokerStreamServer
@@ -53,6 +63,12 @@ func (s *BrokerStreamServer) Associate(stream Broker_AssociateServer) (err error
 		ctx.Debug("Closed Associate stream")
 	}()
 
+	if s.DownlinkOptionsSort != "" {
+		if err := grpc.SendHeader(stream.Context(), metadata.Pairs(DownlinkOptionsSortKey, s.DownlinkOptionsSort)); err != nil {
+			return err
+		}
+	}
+
 	upErr := make(chan error)
 	go func() (err error) {
 		defer func() {
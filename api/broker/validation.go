@@ -24,6 +24,9 @@ func (m *DownlinkOption) Validate() error {
 
 // Validate implements the api.Validator interface
 func (m *UplinkMessage) Validate() error {
+	if m.DownlinkResult != nil {
+		return m.DownlinkResult.Validate()
+	}
 	if err := api.NotNilAndValid(m.ProtocolMetadata, "ProtocolMetadata"); err != nil {
 		return err
 	}
@@ -129,3 +132,17 @@ func (m *ApplicationHandlerRegistration) Validate() error {
 	}
 	return nil
 }
+
+// Validate implements the api.Validator interface
+func (m *DownlinkResult) Validate() error {
+	if err := api.NotEmptyAndValidID(m.DevId, "DevId"); err != nil {
+		return err
+	}
+	if err := api.NotEmptyAndValidID(m.AppId, "AppId"); err != nil {
+		return err
+	}
+	if m.Accepted && m.GatewayId == "" {
+		return errors.NewErrInvalidArgument("GatewayId", "can not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package discoverytest provides an in-memory discovery client for tests
+package discoverytest
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// MemoryClient is an in-memory implementation of discovery.Client that can be
+// used in tests. Announcements can be added with Add and AddBroker, and failures can
+// be injected with SetError.
+type MemoryClient struct {
+	sync.RWMutex
+	self     *discovery.Announcement
+	services map[string][]*discovery.Announcement
+	err      error
+}
+
+// NewMemoryClient returns a new MemoryClient for the given announcement
+func NewMemoryClient(self *discovery.Announcement) *MemoryClient {
+	return &MemoryClient{
+		self:     self,
+		services: make(map[string][]*discovery.Announcement),
+	}
+}
+
+// Add adds (or replaces) an announcement
+func (c *MemoryClient) Add(announcement *discovery.Announcement) {
+	c.Lock()
+	defer c.Unlock()
+	c.add(announcement)
+}
+
+func (c *MemoryClient) add(announcement *discovery.Announcement) {
+	services := c.services[announcement.ServiceName]
+	for i, service := range services {
+		if service.Id == announcement.Id {
+			services[i] = announcement
+			return
+		}
+	}
+	c.services[announcement.ServiceName] = append(services, announcement)
+}
+
+// AddBroker adds the announcement of a broker that handles the given prefixes
+func (c *MemoryClient) AddBroker(id string, prefixes ...types.DevAddrPrefix) *discovery.Announcement {
+	announcement := &discovery.Announcement{
+		ServiceName: "broker",
+		Id:          id,
+		NetAddress:  fmt.Sprintf("%s:1902", id),
+	}
+	for _, prefix := range prefixes {
+		announcement.Metadata = append(announcement.Metadata, &discovery.Metadata{Metadata: &discovery.Metadata_DevAddrPrefix{
+			DevAddrPrefix: prefix.Bytes(),
+		}})
+	}
+	c.Add(announcement)
+	return announcement
+}
+
+// SetError makes all subsequent calls return the given error. Use nil to
+// stop injecting failures.
+func (c *MemoryClient) SetError(err error) {
+	c.Lock()
+	defer c.Unlock()
+	c.err = err
+}
+
+// Announce adds the announcement of the client itself
+func (c *MemoryClient) Announce(token string) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.add(c.self)
+	return nil
+}
+
+// GetAll returns all services of the given service type
+func (c *MemoryClient) GetAll(serviceName string) ([]*discovery.Announcement, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	return append([]*discovery.Announcement{}, c.services[serviceName]...), nil
+}
+
+// Get returns the service annoucement for the given service type and id
+func (c *MemoryClient) Get(serviceName, id string) (*discovery.Announcement, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	for _, service := range c.services[serviceName] {
+		if service.Id == id {
+			return service, nil
+		}
+	}
+	return nil, errors.NewErrNotFound(fmt.Sprintf("%s \"%s\"", serviceName, id))
+}
+
+func (c *MemoryClient) addMetadata(metadata *discovery.Metadata) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.self.Metadata = append(c.self.Metadata, metadata)
+	return nil
+}
+
+func (c *MemoryClient) removeMetadata(metadata *discovery.Metadata) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	for i, existing := range c.self.Metadata {
+		if existing.GetAppId() == metadata.GetAppId() && bytes.Equal(existing.GetDevAddrPrefix(), metadata.GetDevAddrPrefix()) {
+			c.self.Metadata = append(c.self.Metadata[:i], c.self.Metadata[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// AddDevAddrPrefix adds a DevAddrPrefix to the client itself
+func (c *MemoryClient) AddDevAddrPrefix(prefix types.DevAddrPrefix) error {
+	return c.addMetadata(&discovery.Metadata{Metadata: &discovery.Metadata_DevAddrPrefix{DevAddrPrefix: prefix.Bytes()}})
+}
+
+// AddAppID adds an AppID to the client itself
+func (c *MemoryClient) AddAppID(appID string, token string) error {
+	return c.addMetadata(&discovery.Metadata{Metadata: &discovery.Metadata_AppId{AppId: appID}})
+}
+
+// RemoveDevAddrPrefix removes a DevAddrPrefix from the client itself
+func (c *MemoryClient) RemoveDevAddrPrefix(prefix types.DevAddrPrefix) error {
+	return c.removeMetadata(&discovery.Metadata{Metadata: &discovery.Metadata_DevAddrPrefix{DevAddrPrefix: prefix.Bytes()}})
+}
+
+// RemoveAppID removes an AppID from the client itself
+func (c *MemoryClient) RemoveAppID(appID string, token string) error {
+	return c.removeMetadata(&discovery.Metadata{Metadata: &discovery.Metadata_AppId{AppId: appID}})
+}
+
+// GetAllBrokersForDevAddr returns all brokers that can handle the given DevAddr
+func (c *MemoryClient) GetAllBrokersForDevAddr(devAddr types.DevAddr) ([]*discovery.Announcement, error) {
+	brokers, err := c.GetAll("broker")
+	if err != nil {
+		return nil, err
+	}
+	var announcements []*discovery.Announcement
+next:
+	for _, broker := range brokers {
+		for _, prefix := range broker.DevAddrPrefixes() {
+			if devAddr.HasPrefix(prefix) {
+				announcements = append(announcements, broker)
+				continue next
+			}
+		}
+	}
+	return announcements, nil
+}
+
+// GetAllHandlersForAppID returns all handlers that can handle the given AppID
+func (c *MemoryClient) GetAllHandlersForAppID(appID string) ([]*discovery.Announcement, error) {
+	handlers, err := c.GetAll("handler")
+	if err != nil {
+		return nil, err
+	}
+	var announcements []*discovery.Announcement
+next:
+	for _, handler := range handlers {
+		for _, handlerAppID := range handler.AppIDs() {
+			if handlerAppID == appID {
+				announcements = append(announcements, handler)
+				continue next
+			}
+		}
+	}
+	return announcements, nil
+}
+
+// Close does nothing
+func (c *MemoryClient) Close() error {
+	return nil
+}
+
+var _ discovery.Client = &MemoryClient{}
@@ -218,9 +218,11 @@ func (c *TxConfiguration) SetDataRate(dataRate band.DataRate) error {
 			return err
 		}
 		c.DataRate = datr.String()
+		c.BitRate = 0
 	case band.FSKModulation:
 		c.Modulation = Modulation_FSK
 		c.BitRate = uint32(dataRate.BitRate)
+		c.DataRate = ""
 	}
 	return nil
 }
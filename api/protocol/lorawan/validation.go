@@ -1,8 +1,11 @@
 package lorawan
 
 import (
+	"fmt"
+
 	"github.com/TheThingsNetwork/ttn/api"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/brocaar/lorawan"
 )
 
 // Validate implements the api.Validator interface
@@ -159,3 +162,29 @@ func (m *MACPayload) Validate() error {
 	}
 	return nil
 }
+
+// ValidateDownlinkFrame checks if the payload is a valid LoRaWAN downlink frame.
+// Proprietary frames are only checked for a valid MHDR, as their contents are
+// not defined by the LoRaWAN specification.
+func ValidateDownlinkFrame(payload []byte) error {
+	if len(payload) == 0 {
+		return errors.NewErrInvalidArgument("Payload", "can not be empty")
+	}
+	if major := lorawan.Major(payload[0] & 0x03); major != lorawan.LoRaWANR1 {
+		return errors.NewErrInvalidArgument("Major", fmt.Sprintf("invalid value %d", major))
+	}
+	switch mType := lorawan.MType(payload[0] >> 5); mType {
+	case lorawan.JoinAccept:
+		if len(payload) != 17 && len(payload) != 33 {
+			return errors.NewErrInvalidArgument("Payload", fmt.Sprintf("invalid length %d for JoinAccept", len(payload)))
+		}
+	case lorawan.UnconfirmedDataDown, lorawan.ConfirmedDataDown:
+		if len(payload) < 12 { // MHDR, FHDR and MIC
+			return errors.NewErrInvalidArgument("Payload", fmt.Sprintf("invalid length %d for data downlink", len(payload)))
+		}
+	case lorawan.Proprietary:
+	default:
+		return errors.NewErrInvalidArgument("MType", fmt.Sprintf("%d is not a downlink type", mType))
+	}
+	return nil
+}
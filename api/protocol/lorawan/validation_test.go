@@ -0,0 +1,37 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package lorawan
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateDownlinkFrame(t *testing.T) {
+	a := New(t)
+
+	a.So(ValidateDownlinkFrame([]byte{}), ShouldNotBeNil)
+
+	// Uplink frames are not valid downlinks
+	a.So(ValidateDownlinkFrame(make([]byte, 12)), ShouldNotBeNil)
+	a.So(ValidateDownlinkFrame(append([]byte{0x40}, make([]byte, 11)...)), ShouldNotBeNil)
+
+	// Data downlinks need at least MHDR, FHDR and MIC
+	a.So(ValidateDownlinkFrame(append([]byte{0x60}, make([]byte, 11)...)), ShouldBeNil)
+	a.So(ValidateDownlinkFrame(append([]byte{0xa0}, make([]byte, 11)...)), ShouldBeNil)
+	a.So(ValidateDownlinkFrame([]byte{0x60, 0x01}), ShouldNotBeNil)
+
+	// Join accepts have a fixed length
+	a.So(ValidateDownlinkFrame(append([]byte{0x20}, make([]byte, 16)...)), ShouldBeNil)
+	a.So(ValidateDownlinkFrame(append([]byte{0x20}, make([]byte, 32)...)), ShouldBeNil)
+	a.So(ValidateDownlinkFrame(append([]byte{0x20}, make([]byte, 20)...)), ShouldNotBeNil)
+
+	// Proprietary frames are accepted with any contents
+	a.So(ValidateDownlinkFrame([]byte{0xe0}), ShouldBeNil)
+	a.So(ValidateDownlinkFrame([]byte{0xe0, 0x01, 0x02, 0x03}), ShouldBeNil)
+
+	// Unknown major versions are rejected
+	a.So(ValidateDownlinkFrame([]byte{0xe1}), ShouldNotBeNil)
+}
@@ -24,6 +24,7 @@ type RouterClientForGateway interface {
 	Uplink() (Router_UplinkClient, error)
 	Subscribe() (Router_SubscribeClient, context.CancelFunc, error)
 	Activate(in *DeviceActivationRequest) (*DeviceActivationResponse, error)
+	TxAck() (Router_TxAckClient, error)
 }
 
 // NewRouterClientForGateway returns a new RouterClient for the given gateway ID and access token
@@ -98,3 +99,8 @@ func (c *routerClientForGateway) Activate(in *DeviceActivationRequest) (*DeviceA
 	c.ctx.Debug("Calling Activate")
 	return c.client.Activate(c.getContext(), in)
 }
+
+func (c *routerClientForGateway) TxAck() (Router_TxAckClient, error) {
+	c.ctx.Debug("Starting TxAck stream")
+	return c.client.TxAck(c.getContext())
+}
@@ -18,6 +18,12 @@
 		GatewayStatusResponse
 		StatusRequest
 		Status
+		DownlinkSafeModeRequest
+		CapabilitiesRequest
+		Capabilities
+		ClearGatewayScheduleRequest
+		ClearGatewayScheduleResponse
+		TxAcknowledgment
 */
 package router
 
@@ -50,6 +56,47 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type TxAcknowledgment_Error int32
+
+const (
+	TxAcknowledgment_NONE             TxAcknowledgment_Error = 0
+	TxAcknowledgment_TOO_LATE         TxAcknowledgment_Error = 1
+	TxAcknowledgment_TOO_EARLY        TxAcknowledgment_Error = 2
+	TxAcknowledgment_COLLISION_PACKET TxAcknowledgment_Error = 3
+	TxAcknowledgment_COLLISION_BEACON TxAcknowledgment_Error = 4
+	TxAcknowledgment_TX_FREQ          TxAcknowledgment_Error = 5
+	TxAcknowledgment_TX_POWER         TxAcknowledgment_Error = 6
+	TxAcknowledgment_GPS_UNLOCKED     TxAcknowledgment_Error = 7
+)
+
+var TxAcknowledgment_Error_name = map[int32]string{
+	0: "NONE",
+	1: "TOO_LATE",
+	2: "TOO_EARLY",
+	3: "COLLISION_PACKET",
+	4: "COLLISION_BEACON",
+	5: "TX_FREQ",
+	6: "TX_POWER",
+	7: "GPS_UNLOCKED",
+}
+var TxAcknowledgment_Error_value = map[string]int32{
+	"NONE":             0,
+	"TOO_LATE":         1,
+	"TOO_EARLY":        2,
+	"COLLISION_PACKET": 3,
+	"COLLISION_BEACON": 4,
+	"TX_FREQ":          5,
+	"TX_POWER":         6,
+	"GPS_UNLOCKED":     7,
+}
+
+func (x TxAcknowledgment_Error) String() string {
+	return proto.EnumName(TxAcknowledgment_Error_name, int32(x))
+}
+func (TxAcknowledgment_Error) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorRouter, []int{14, 0}
+}
+
 type SubscribeRequest struct {
 }
 
@@ -96,6 +143,10 @@ type DownlinkMessage struct {
 	Message               *protocol.Message         `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
 	ProtocolConfiguration *protocol.TxConfiguration `protobuf:"bytes,11,opt,name=protocol_configuration,json=protocolConfiguration" json:"protocol_configuration,omitempty"`
 	GatewayConfiguration  *gateway.TxConfiguration  `protobuf:"bytes,12,opt,name=gateway_configuration,json=gatewayConfiguration" json:"gateway_configuration,omitempty"`
+	// Identifier of the downlink in the schedule of the gateway, which the
+	// gateway returns in its TxAcknowledgment
+	Identifier string `protobuf:"bytes,21,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	TraceId    string `protobuf:"bytes,31,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
 }
 
 func (m *DownlinkMessage) Reset()                    { *m = DownlinkMessage{} }
@@ -189,6 +240,10 @@ func (*GatewayStatusRequest) Descriptor() ([]byte, []int) { return fileDescripto
 type GatewayStatusResponse struct {
 	LastSeen int64           `protobuf:"varint,1,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
 	Status   *gateway.Status `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+	// Time (in ns) between handling the last downlink and its transmission
+	LeadTime int64 `protobuf:"varint,3,opt,name=lead_time,json=leadTime,proto3" json:"lead_time,omitempty"`
+	// Highest lead time (in ns) of the downlink of the gateway
+	MaxLeadTime int64 `protobuf:"varint,4,opt,name=max_lead_time,json=maxLeadTime,proto3" json:"max_lead_time,omitempty"`
 }
 
 func (m *GatewayStatusResponse) Reset()                    { *m = GatewayStatusResponse{} }
@@ -272,6 +327,81 @@ func (m *Status) GetActivations() *api.Rates {
 	return nil
 }
 
+// message DownlinkSafeModeRequest is used to enable or disable the safe mode
+// in which the Router does not send any downlink
+type DownlinkSafeModeRequest struct {
+	DownlinksDisabled bool `protobuf:"varint,1,opt,name=downlinks_disabled,json=downlinksDisabled,proto3" json:"downlinks_disabled,omitempty"`
+}
+
+func (m *DownlinkSafeModeRequest) Reset()                    { *m = DownlinkSafeModeRequest{} }
+func (m *DownlinkSafeModeRequest) String() string            { return proto.CompactTextString(m) }
+func (*DownlinkSafeModeRequest) ProtoMessage()               {}
+func (*DownlinkSafeModeRequest) Descriptor() ([]byte, []int) { return fileDescriptorRouter, []int{9} }
+
+// message CapabilitiesRequest is used to request the capabilities of this Router
+type CapabilitiesRequest struct {
+}
+
+func (m *CapabilitiesRequest) Reset()                    { *m = CapabilitiesRequest{} }
+func (m *CapabilitiesRequest) String() string            { return proto.CompactTextString(m) }
+func (*CapabilitiesRequest) ProtoMessage()               {}
+func (*CapabilitiesRequest) Descriptor() ([]byte, []int) { return fileDescriptorRouter, []int{10} }
+
+// message Capabilities is the response to the CapabilitiesRequest
+type Capabilities struct {
+	// Frequency plans (regions) that are supported by this Router
+	FrequencyPlans []string `protobuf:"bytes,1,rep,name=frequency_plans,json=frequencyPlans" json:"frequency_plans,omitempty"`
+	// Modulations that are supported by this Router
+	Modulations []string `protobuf:"bytes,2,rep,name=modulations" json:"modulations,omitempty"`
+	// Features that are supported and enabled in this Router
+	Features []string `protobuf:"bytes,3,rep,name=features" json:"features,omitempty"`
+}
+
+func (m *Capabilities) Reset()                    { *m = Capabilities{} }
+func (m *Capabilities) String() string            { return proto.CompactTextString(m) }
+func (*Capabilities) ProtoMessage()               {}
+func (*Capabilities) Descriptor() ([]byte, []int) { return fileDescriptorRouter, []int{11} }
+
+// message ClearGatewayScheduleRequest is used to cancel all downlink of a
+// gateway that was not yet transmitted
+type ClearGatewayScheduleRequest struct {
+	GatewayId string `protobuf:"bytes,1,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`
+}
+
+func (m *ClearGatewayScheduleRequest) Reset()         { *m = ClearGatewayScheduleRequest{} }
+func (m *ClearGatewayScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*ClearGatewayScheduleRequest) ProtoMessage()    {}
+func (*ClearGatewayScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorRouter, []int{12}
+}
+
+// message ClearGatewayScheduleResponse is the response to the ClearGatewayScheduleRequest
+type ClearGatewayScheduleResponse struct {
+	// Number of cancelled downlink options and transmissions
+	Cleared uint32 `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
+}
+
+func (m *ClearGatewayScheduleResponse) Reset()         { *m = ClearGatewayScheduleResponse{} }
+func (m *ClearGatewayScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*ClearGatewayScheduleResponse) ProtoMessage()    {}
+func (*ClearGatewayScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorRouter, []int{13}
+}
+
+// message TxAcknowledgment is sent by a Gateway after it transmitted a
+// DownlinkMessage, or after it failed to transmit it
+type TxAcknowledgment struct {
+	// Identifier of the DownlinkMessage
+	Identifier string `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	// Reason why the Gateway did not transmit the DownlinkMessage (NONE if it did)
+	Error TxAcknowledgment_Error `protobuf:"varint,2,opt,name=error,proto3,enum=router.TxAcknowledgment_Error" json:"error,omitempty"`
+}
+
+func (m *TxAcknowledgment) Reset()                    { *m = TxAcknowledgment{} }
+func (m *TxAcknowledgment) String() string            { return proto.CompactTextString(m) }
+func (*TxAcknowledgment) ProtoMessage()               {}
+func (*TxAcknowledgment) Descriptor() ([]byte, []int) { return fileDescriptorRouter, []int{14} }
+
 func init() {
 	proto.RegisterType((*SubscribeRequest)(nil), "router.SubscribeRequest")
 	proto.RegisterType((*UplinkMessage)(nil), "router.UplinkMessage")
@@ -282,6 +412,13 @@ func init() {
 	proto.RegisterType((*GatewayStatusResponse)(nil), "router.GatewayStatusResponse")
 	proto.RegisterType((*StatusRequest)(nil), "router.StatusRequest")
 	proto.RegisterType((*Status)(nil), "router.Status")
+	proto.RegisterType((*DownlinkSafeModeRequest)(nil), "router.DownlinkSafeModeRequest")
+	proto.RegisterType((*CapabilitiesRequest)(nil), "router.CapabilitiesRequest")
+	proto.RegisterType((*Capabilities)(nil), "router.Capabilities")
+	proto.RegisterType((*ClearGatewayScheduleRequest)(nil), "router.ClearGatewayScheduleRequest")
+	proto.RegisterType((*ClearGatewayScheduleResponse)(nil), "router.ClearGatewayScheduleResponse")
+	proto.RegisterType((*TxAcknowledgment)(nil), "router.TxAcknowledgment")
+	proto.RegisterEnum("router.TxAcknowledgment_Error", TxAcknowledgment_Error_name, TxAcknowledgment_Error_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -305,6 +442,8 @@ type RouterClient interface {
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Router_SubscribeClient, error)
 	// Gateway requests device activation
 	Activate(ctx context.Context, in *DeviceActivationRequest, opts ...grpc.CallOption) (*DeviceActivationResponse, error)
+	// Gateway streams the acknowledgements of the transmission of downlink messages to Router
+	TxAck(ctx context.Context, opts ...grpc.CallOption) (Router_TxAckClient, error)
 }
 
 type routerClient struct {
@@ -424,6 +563,40 @@ func (c *routerClient) Activate(ctx context.Context, in *DeviceActivationRequest
 	return out, nil
 }
 
+func (c *routerClient) TxAck(ctx context.Context, opts ...grpc.CallOption) (Router_TxAckClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Router_serviceDesc.Streams[3], c.cc, "/router.Router/TxAck", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerTxAckClient{stream}
+	return x, nil
+}
+
+type Router_TxAckClient interface {
+	Send(*TxAcknowledgment) error
+	CloseAndRecv() (*google_protobuf.Empty, error)
+	grpc.ClientStream
+}
+
+type routerTxAckClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerTxAckClient) Send(m *TxAcknowledgment) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *routerTxAckClient) CloseAndRecv() (*google_protobuf.Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(google_protobuf.Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Router service
 
 type RouterServer interface {
@@ -437,6 +610,8 @@ type RouterServer interface {
 	Subscribe(*SubscribeRequest, Router_SubscribeServer) error
 	// Gateway requests device activation
 	Activate(context.Context, *DeviceActivationRequest) (*DeviceActivationResponse, error)
+	// Gateway streams the acknowledgements of the transmission of downlink messages to Router
+	TxAck(Router_TxAckServer) error
 }
 
 func RegisterRouterServer(s *grpc.Server, srv RouterServer) {
@@ -534,6 +709,32 @@ func _Router_Activate_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_TxAck_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RouterServer).TxAck(&routerTxAckServer{stream})
+}
+
+type Router_TxAckServer interface {
+	SendAndClose(*google_protobuf.Empty) error
+	Recv() (*TxAcknowledgment, error)
+	grpc.ServerStream
+}
+
+type routerTxAckServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerTxAckServer) SendAndClose(m *google_protobuf.Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *routerTxAckServer) Recv() (*TxAcknowledgment, error) {
+	m := new(TxAcknowledgment)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Router_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "router.Router",
 	HandlerType: (*RouterServer)(nil),
@@ -559,6 +760,11 @@ var _Router_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Router_Subscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "TxAck",
+			Handler:       _Router_TxAck_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "github.com/TheThingsNetwork/ttn/api/router/router.proto",
 }
@@ -570,6 +776,12 @@ type RouterManagerClient interface {
 	GatewayStatus(ctx context.Context, in *GatewayStatusRequest, opts ...grpc.CallOption) (*GatewayStatusResponse, error)
 	// Network operator requests Router status
 	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// Network operator enables or disables the downlink safe mode of the Router
+	SetDownlinkSafeMode(ctx context.Context, in *DownlinkSafeModeRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// Broker or network operator requests the capabilities of the Router
+	GetCapabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*Capabilities, error)
+	// Network operator cancels all pending downlink of a Gateway
+	ClearGatewaySchedule(ctx context.Context, in *ClearGatewayScheduleRequest, opts ...grpc.CallOption) (*ClearGatewayScheduleResponse, error)
 }
 
 type routerManagerClient struct {
@@ -598,6 +810,33 @@ func (c *routerManagerClient) GetStatus(ctx context.Context, in *StatusRequest,
 	return out, nil
 }
 
+func (c *routerManagerClient) SetDownlinkSafeMode(ctx context.Context, in *DownlinkSafeModeRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/router.RouterManager/SetDownlinkSafeMode", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerManagerClient) GetCapabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*Capabilities, error) {
+	out := new(Capabilities)
+	err := grpc.Invoke(ctx, "/router.RouterManager/GetCapabilities", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerManagerClient) ClearGatewaySchedule(ctx context.Context, in *ClearGatewayScheduleRequest, opts ...grpc.CallOption) (*ClearGatewayScheduleResponse, error) {
+	out := new(ClearGatewayScheduleResponse)
+	err := grpc.Invoke(ctx, "/router.RouterManager/ClearGatewaySchedule", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for RouterManager service
 
 type RouterManagerServer interface {
@@ -605,6 +844,12 @@ type RouterManagerServer interface {
 	GatewayStatus(context.Context, *GatewayStatusRequest) (*GatewayStatusResponse, error)
 	// Network operator requests Router status
 	GetStatus(context.Context, *StatusRequest) (*Status, error)
+	// Network operator enables or disables the downlink safe mode of the Router
+	SetDownlinkSafeMode(context.Context, *DownlinkSafeModeRequest) (*google_protobuf.Empty, error)
+	// Broker or network operator requests the capabilities of the Router
+	GetCapabilities(context.Context, *CapabilitiesRequest) (*Capabilities, error)
+	// Network operator cancels all pending downlink of a Gateway
+	ClearGatewaySchedule(context.Context, *ClearGatewayScheduleRequest) (*ClearGatewayScheduleResponse, error)
 }
 
 func RegisterRouterManagerServer(s *grpc.Server, srv RouterManagerServer) {
@@ -647,6 +892,60 @@ func _RouterManager_GetStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RouterManager_SetDownlinkSafeMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownlinkSafeModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterManagerServer).SetDownlinkSafeMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/router.RouterManager/SetDownlinkSafeMode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterManagerServer).SetDownlinkSafeMode(ctx, req.(*DownlinkSafeModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RouterManager_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterManagerServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/router.RouterManager/GetCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterManagerServer).GetCapabilities(ctx, req.(*CapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RouterManager_ClearGatewaySchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearGatewayScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterManagerServer).ClearGatewaySchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/router.RouterManager/ClearGatewaySchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterManagerServer).ClearGatewaySchedule(ctx, req.(*ClearGatewayScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RouterManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "router.RouterManager",
 	HandlerType: (*RouterManagerServer)(nil),
@@ -659,6 +958,18 @@ var _RouterManager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStatus",
 			Handler:    _RouterManager_GetStatus_Handler,
 		},
+		{
+			MethodName: "SetDownlinkSafeMode",
+			Handler:    _RouterManager_SetDownlinkSafeMode_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _RouterManager_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "ClearGatewaySchedule",
+			Handler:    _RouterManager_ClearGatewaySchedule_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "github.com/TheThingsNetwork/ttn/api/router/router.proto",
@@ -787,6 +1098,22 @@ func (m *DownlinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n6
 	}
+	if len(m.Identifier) > 0 {
+		dAtA[i] = 0xaa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(len(m.Identifier)))
+		i += copy(dAtA[i:], m.Identifier)
+	}
+	if len(m.TraceId) > 0 {
+		dAtA[i] = 0xfa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(len(m.TraceId)))
+		i += copy(dAtA[i:], m.TraceId)
+	}
 	return i, nil
 }
 
@@ -952,6 +1279,16 @@ func (m *GatewayStatusResponse) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n13
 	}
+	if m.LeadTime != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(m.LeadTime))
+	}
+	if m.MaxLeadTime != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(m.MaxLeadTime))
+	}
 	return i, nil
 }
 
@@ -1065,70 +1402,255 @@ func (m *Status) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func encodeFixed64Router(dAtA []byte, offset int, v uint64) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	dAtA[offset+4] = uint8(v >> 32)
-	dAtA[offset+5] = uint8(v >> 40)
-	dAtA[offset+6] = uint8(v >> 48)
-	dAtA[offset+7] = uint8(v >> 56)
-	return offset + 8
+func (m *DownlinkSafeModeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
-func encodeFixed32Router(dAtA []byte, offset int, v uint32) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	return offset + 4
+
+func (m *DownlinkSafeModeRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.DownlinksDisabled {
+		dAtA[i] = 0x8
+		i++
+		if m.DownlinksDisabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
 }
-func encodeVarintRouter(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+
+func (m *CapabilitiesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *SubscribeRequest) Size() (n int) {
+
+func (m *CapabilitiesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	return n
+	return i, nil
 }
 
-func (m *UplinkMessage) Size() (n int) {
+func (m *Capabilities) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Capabilities) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovRouter(uint64(l))
+	if len(m.FrequencyPlans) > 0 {
+		for _, s := range m.FrequencyPlans {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	if m.Message != nil {
-		l = m.Message.Size()
-		n += 1 + l + sovRouter(uint64(l))
+	if len(m.Modulations) > 0 {
+		for _, s := range m.Modulations {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	if m.ProtocolMetadata != nil {
-		l = m.ProtocolMetadata.Size()
-		n += 1 + l + sovRouter(uint64(l))
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	if m.GatewayMetadata != nil {
-		l = m.GatewayMetadata.Size()
-		n += 1 + l + sovRouter(uint64(l))
+	return i, nil
+}
+
+func (m *ClearGatewayScheduleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *DownlinkMessage) Size() (n int) {
+func (m *ClearGatewayScheduleRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovRouter(uint64(l))
+	if len(m.GatewayId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(len(m.GatewayId)))
+		i += copy(dAtA[i:], m.GatewayId)
 	}
-	if m.Message != nil {
-		l = m.Message.Size()
+	return i, nil
+}
+
+func (m *ClearGatewayScheduleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClearGatewayScheduleResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Cleared != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(m.Cleared))
+	}
+	return i, nil
+}
+
+func (m *TxAcknowledgment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxAcknowledgment) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Identifier) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(len(m.Identifier)))
+		i += copy(dAtA[i:], m.Identifier)
+	}
+	if m.Error != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(m.Error))
+	}
+	return i, nil
+}
+
+func encodeFixed64Router(dAtA []byte, offset int, v uint64) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	dAtA[offset+4] = uint8(v >> 32)
+	dAtA[offset+5] = uint8(v >> 40)
+	dAtA[offset+6] = uint8(v >> 48)
+	dAtA[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Router(dAtA []byte, offset int, v uint32) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintRouter(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *SubscribeRequest) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *UplinkMessage) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	if m.Message != nil {
+		l = m.Message.Size()
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	if m.ProtocolMetadata != nil {
+		l = m.ProtocolMetadata.Size()
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	if m.GatewayMetadata != nil {
+		l = m.GatewayMetadata.Size()
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	return n
+}
+
+func (m *DownlinkMessage) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	if m.Message != nil {
+		l = m.Message.Size()
 		n += 1 + l + sovRouter(uint64(l))
 	}
 	if m.ProtocolConfiguration != nil {
@@ -1139,6 +1661,14 @@ func (m *DownlinkMessage) Size() (n int) {
 		l = m.GatewayConfiguration.Size()
 		n += 1 + l + sovRouter(uint64(l))
 	}
+	l = len(m.Identifier)
+	if l > 0 {
+		n += 2 + l + sovRouter(uint64(l))
+	}
+	l = len(m.TraceId)
+	if l > 0 {
+		n += 2 + l + sovRouter(uint64(l))
+	}
 	return n
 }
 
@@ -1202,6 +1732,12 @@ func (m *GatewayStatusResponse) Size() (n int) {
 		l = m.Status.Size()
 		n += 1 + l + sovRouter(uint64(l))
 	}
+	if m.LeadTime != 0 {
+		n += 1 + sovRouter(uint64(m.LeadTime))
+	}
+	if m.MaxLeadTime != 0 {
+		n += 1 + sovRouter(uint64(m.MaxLeadTime))
+	}
 	return n
 }
 
@@ -1247,6 +1783,77 @@ func (m *Status) Size() (n int) {
 	return n
 }
 
+func (m *DownlinkSafeModeRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.DownlinksDisabled {
+		n += 2
+	}
+	return n
+}
+
+func (m *CapabilitiesRequest) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *Capabilities) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.FrequencyPlans) > 0 {
+		for _, s := range m.FrequencyPlans {
+			l = len(s)
+			n += 1 + l + sovRouter(uint64(l))
+		}
+	}
+	if len(m.Modulations) > 0 {
+		for _, s := range m.Modulations {
+			l = len(s)
+			n += 1 + l + sovRouter(uint64(l))
+		}
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			l = len(s)
+			n += 1 + l + sovRouter(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ClearGatewayScheduleRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.GatewayId)
+	if l > 0 {
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	return n
+}
+
+func (m *ClearGatewayScheduleResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Cleared != 0 {
+		n += 1 + sovRouter(uint64(m.Cleared))
+	}
+	return n
+}
+
+func (m *TxAcknowledgment) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Identifier)
+	if l > 0 {
+		n += 1 + l + sovRouter(uint64(l))
+	}
+	if m.Error != 0 {
+		n += 1 + sovRouter(uint64(m.Error))
+	}
+	return n
+}
+
 func sovRouter(x uint64) (n int) {
 	for {
 		n++
@@ -1649,6 +2256,64 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Identifier", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Identifier = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRouter(dAtA[iNdEx:])
@@ -2157,6 +2822,44 @@ func (m *GatewayStatusResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeadTime", wireType)
+			}
+			m.LeadTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LeadTime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxLeadTime", wireType)
+			}
+			m.MaxLeadTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxLeadTime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRouter(dAtA[iNdEx:])
@@ -2514,6 +3217,509 @@ func (m *Status) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *DownlinkSafeModeRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DownlinkSafeModeRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DownlinkSafeModeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DownlinksDisabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DownlinksDisabled = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CapabilitiesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CapabilitiesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CapabilitiesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Capabilities) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Capabilities: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Capabilities: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FrequencyPlans", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FrequencyPlans = append(m.FrequencyPlans, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Modulations", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Modulations = append(m.Modulations, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Features", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Features = append(m.Features, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClearGatewayScheduleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClearGatewayScheduleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClearGatewayScheduleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GatewayId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GatewayId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClearGatewayScheduleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClearGatewayScheduleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClearGatewayScheduleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cleared", wireType)
+			}
+			m.Cleared = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Cleared |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TxAcknowledgment) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRouter
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxAcknowledgment: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxAcknowledgment: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Identifier", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Identifier = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			m.Error = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Error |= (TxAcknowledgment_Error(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRouter(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRouter
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipRouter(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -2624,59 +3830,86 @@ func init() {
 }
 
 var fileDescriptorRouter = []byte{
-	// 853 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x56, 0xcd, 0x6f, 0xe3, 0x44,
-	0x14, 0xc7, 0x5d, 0xc9, 0x6d, 0x5e, 0xeb, 0x36, 0x9d, 0x36, 0xad, 0xc9, 0x6e, 0x3f, 0xe4, 0x03,
-	0x54, 0x2c, 0xeb, 0xd0, 0xa0, 0x15, 0x02, 0x21, 0x44, 0xbb, 0xad, 0x56, 0x2b, 0x91, 0x15, 0x72,
-	0xbb, 0x17, 0x24, 0x14, 0x4d, 0x9c, 0xb7, 0xae, 0xd5, 0xc4, 0x63, 0x3c, 0xe3, 0x74, 0xf3, 0x5f,
-	0xc0, 0x8d, 0x3f, 0x89, 0x23, 0xe2, 0x06, 0x07, 0x84, 0xca, 0x9d, 0x3b, 0x37, 0xe4, 0xf9, 0xb0,
-	0xe3, 0xb4, 0x0b, 0x15, 0xb0, 0xa7, 0x78, 0x7e, 0xef, 0xf7, 0x7e, 0x33, 0xef, 0x63, 0xe6, 0x05,
-	0x3e, 0x8a, 0x62, 0x71, 0x91, 0x0f, 0xfc, 0x90, 0x8d, 0x3b, 0xe7, 0x17, 0x78, 0x7e, 0x11, 0x27,
-	0x11, 0x7f, 0x8e, 0xe2, 0x8a, 0x65, 0x97, 0x1d, 0x21, 0x92, 0x0e, 0x4d, 0xe3, 0x4e, 0xc6, 0x72,
-	0x81, 0x99, 0xfe, 0xf1, 0xd3, 0x8c, 0x09, 0x46, 0x6c, 0xb5, 0x6a, 0xdf, 0x8f, 0x18, 0x8b, 0x46,
-	0xd8, 0x91, 0xe8, 0x20, 0x7f, 0xd9, 0xc1, 0x71, 0x2a, 0xa6, 0x8a, 0xd4, 0x7e, 0x34, 0xa3, 0x1e,
-	0xb1, 0x88, 0x55, 0xac, 0x62, 0x25, 0x17, 0xf2, 0x4b, 0xd3, 0xd7, 0xcd, 0x86, 0x34, 0x8d, 0x35,
-	0xb4, 0x67, 0x20, 0xb9, 0x0c, 0xd9, 0xa8, 0xfc, 0xd0, 0x84, 0x1d, 0x43, 0x88, 0xa8, 0xc0, 0x2b,
-	0x3a, 0x35, 0xbf, 0xca, 0xec, 0x11, 0x68, 0x9e, 0xe5, 0x03, 0x1e, 0x66, 0xf1, 0x00, 0x03, 0xfc,
-	0x26, 0x47, 0x2e, 0xbc, 0x9f, 0x2d, 0x70, 0x5e, 0xa4, 0xa3, 0x38, 0xb9, 0xec, 0x21, 0xe7, 0x34,
-	0x42, 0xe2, 0xc2, 0x62, 0x4a, 0xa7, 0x23, 0x46, 0x87, 0xae, 0xb5, 0x6f, 0x1d, 0xac, 0x04, 0x66,
-	0x49, 0x1e, 0xc2, 0xe2, 0x58, 0x91, 0xdc, 0x85, 0x7d, 0xeb, 0x60, 0xb9, 0xbb, 0xee, 0x97, 0x07,
-	0xd0, 0xde, 0x81, 0x61, 0x90, 0x23, 0x58, 0x37, 0xc6, 0xfe, 0x18, 0x05, 0x1d, 0x52, 0x41, 0xdd,
-	0x65, 0xe9, 0xb6, 0x59, 0xb9, 0x05, 0xaf, 0x7a, 0xda, 0x16, 0x34, 0x0d, 0x68, 0x10, 0xf2, 0x19,
-	0x34, 0x75, 0x00, 0x95, 0xc2, 0x8a, 0x54, 0xd8, 0xf0, 0x4d, 0x64, 0x33, 0x02, 0x6b, 0x1a, 0x33,
-	0x80, 0xf7, 0xa7, 0x05, 0x6b, 0x27, 0xec, 0x2a, 0x79, 0x03, 0xd1, 0x7d, 0x09, 0x5b, 0x65, 0x74,
-	0x21, 0x4b, 0x5e, 0xc6, 0x51, 0x9e, 0x51, 0x11, 0xb3, 0x44, 0x87, 0xf8, 0x76, 0xe5, 0x7b, 0xfe,
-	0xea, 0xc9, 0x2c, 0x21, 0x68, 0x19, 0x4b, 0x0d, 0x26, 0x3d, 0x68, 0x99, 0x60, 0xeb, 0x82, 0x2a,
-	0x62, 0xb7, 0x8c, 0x78, 0x5e, 0x6f, 0x53, 0x1b, 0x6a, 0xa8, 0xf7, 0xd3, 0x3d, 0xd8, 0x3e, 0xc1,
-	0x49, 0x1c, 0xe2, 0x51, 0x28, 0xe2, 0x89, 0xa2, 0xaa, 0x9a, 0xff, 0x5f, 0x39, 0x78, 0x0e, 0x8b,
-	0x43, 0x9c, 0xf4, 0x31, 0x8f, 0x65, 0xd0, 0x2b, 0xc7, 0x8f, 0x7f, 0xf9, 0x75, 0xef, 0xf0, 0x9f,
-	0xee, 0x50, 0xc8, 0x32, 0xec, 0x88, 0x69, 0x8a, 0xdc, 0x3f, 0xc1, 0xc9, 0xe9, 0x8b, 0x67, 0x81,
-	0x3d, 0xc4, 0xc9, 0x69, 0x1e, 0x17, 0x7a, 0x34, 0x4d, 0xa5, 0xde, 0xca, 0xbf, 0xd2, 0x3b, 0x4a,
-	0x53, 0xa9, 0x47, 0xd3, 0xb4, 0xd0, 0xbb, 0xb5, 0x03, 0x5b, 0xff, 0xb9, 0x03, 0xb7, 0xee, 0xde,
-	0x81, 0xa4, 0x07, 0x1b, 0xb4, 0x4c, 0x7f, 0x25, 0xb1, 0x2d, 0x25, 0x1e, 0x54, 0x87, 0xa8, 0x6a,
-	0x54, 0x6a, 0x11, 0x7a, 0x03, 0xf3, 0xda, 0xe0, 0xde, 0xac, 0x29, 0x4f, 0x59, 0xc2, 0xd1, 0x7b,
-	0x0c, 0x9b, 0x4f, 0xd5, 0xee, 0x67, 0x82, 0x8a, 0x9c, 0x9b, 0x62, 0xef, 0x00, 0x98, 0x10, 0x62,
-	0x55, 0xef, 0x46, 0xd0, 0xd0, 0xc8, 0xb3, 0xa1, 0xf7, 0x35, 0xb4, 0xe6, 0xdc, 0x94, 0x1e, 0xb9,
-	0x0f, 0x8d, 0x11, 0xe5, 0xa2, 0xcf, 0x11, 0x13, 0xe9, 0x76, 0x2f, 0x58, 0x2a, 0x80, 0x33, 0xc4,
-	0x84, 0xbc, 0x0b, 0x36, 0x97, 0x74, 0xdd, 0x26, 0x6b, 0x65, 0x36, 0xb4, 0x8a, 0x36, 0x7b, 0x6b,
-	0xe0, 0xd4, 0x8e, 0xe3, 0xfd, 0xb1, 0x00, 0xb6, 0x42, 0xc8, 0x01, 0xd8, 0x7c, 0xca, 0x05, 0x8e,
-	0xa5, 0xfc, 0x72, 0xb7, 0xe9, 0x17, 0x4f, 0xdd, 0x99, 0x84, 0x0a, 0x4a, 0xa1, 0x22, 0x17, 0xe4,
-	0x10, 0x1a, 0x21, 0x1b, 0xa7, 0x2c, 0xc1, 0x44, 0xe8, 0x1d, 0x37, 0x24, 0xf9, 0x89, 0x41, 0x15,
-	0xbf, 0x62, 0x91, 0x43, 0x58, 0x35, 0x61, 0xeb, 0x93, 0xaa, 0x8b, 0x09, 0xd2, 0x2f, 0xa0, 0x02,
-	0x79, 0xe0, 0x44, 0xb3, 0x91, 0x13, 0x0f, 0xec, 0x5c, 0xbe, 0x84, 0xfa, 0xca, 0xcd, 0x52, 0xb5,
-	0x85, 0xbc, 0x03, 0x4b, 0x43, 0xfd, 0xa2, 0xb8, 0xce, 0x0d, 0x56, 0x69, 0x23, 0xef, 0xc3, 0x72,
-	0x55, 0x3f, 0xee, 0xae, 0xde, 0xa0, 0xce, 0x9a, 0xc9, 0x23, 0x20, 0x21, 0x4b, 0x12, 0x0c, 0x05,
-	0x0e, 0xfb, 0xfa, 0x50, 0x5c, 0xb6, 0xaa, 0x13, 0xac, 0x97, 0x16, 0x5d, 0x27, 0x4e, 0x1e, 0x42,
-	0x05, 0xf6, 0x07, 0x19, 0xbb, 0xc4, 0x8c, 0xcb, 0xb6, 0x74, 0x82, 0x66, 0x69, 0x38, 0x56, 0x78,
-	0xf7, 0xdb, 0x05, 0xb0, 0x03, 0x39, 0x9e, 0xc8, 0x27, 0xe0, 0xd4, 0x6a, 0x4d, 0xe6, 0xcb, 0xd6,
-	0xde, 0xf2, 0xd5, 0x04, 0xf3, 0xcd, 0x6c, 0xf2, 0x4f, 0x8b, 0x09, 0x76, 0x60, 0x91, 0x8f, 0xc1,
-	0x56, 0x63, 0x82, 0xb4, 0x7c, 0x3d, 0xfb, 0x6a, 0x63, 0xe3, 0x6f, 0x5c, 0x3f, 0x87, 0x46, 0x39,
-	0x76, 0x88, 0x6b, 0xbc, 0xe7, 0x27, 0x51, 0x7b, 0xdb, 0x58, 0xe6, 0x9e, 0xec, 0x0f, 0x2c, 0xd2,
-	0x83, 0x25, 0xdd, 0xf1, 0x48, 0xf6, 0x4a, 0xda, 0xed, 0xaf, 0x5b, 0x7b, 0xff, 0xf5, 0x04, 0xd5,
-	0xda, 0xdd, 0xef, 0x2c, 0x70, 0x54, 0x4a, 0x7a, 0x34, 0xa1, 0x11, 0x66, 0xe4, 0x8b, 0xf9, 0xcc,
-	0x3c, 0x30, 0x22, 0xb7, 0xdd, 0xa9, 0xf6, 0xce, 0x6b, 0xac, 0xfa, 0xea, 0x74, 0xa1, 0xf1, 0x14,
-	0x85, 0x56, 0x2a, 0xd3, 0x55, 0x97, 0x58, 0xad, 0xc3, 0xc7, 0x9f, 0xfe, 0x70, 0xbd, 0x6b, 0xfd,
-	0x78, 0xbd, 0x6b, 0xfd, 0x76, 0xbd, 0x6b, 0x7d, 0xff, 0xfb, 0xee, 0x5b, 0x5f, 0xbd, 0x77, 0xf7,
-	0x7f, 0x23, 0x03, 0x5b, 0x26, 0xfd, 0xc3, 0xbf, 0x02, 0x00, 0x00, 0xff, 0xff, 0x43, 0x88, 0x6d,
-	0x23, 0xc2, 0x08, 0x00, 0x00,
+	// 1281 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x5e, 0xb7, 0xdb, 0x34, 0x39, 0x4d, 0x5a, 0x77, 0xda, 0x6e, 0xbd, 0xe9, 0x6e, 0x5b, 0x19,
+	0xc4, 0x56, 0x2c, 0x9b, 0xb0, 0x85, 0x15, 0x7f, 0x2b, 0x44, 0x9a, 0x86, 0x52, 0x6d, 0x7e, 0x8a,
+	0x93, 0x15, 0x0b, 0x37, 0xd6, 0xc4, 0x3e, 0x4d, 0xad, 0x26, 0xb6, 0xb1, 0xc7, 0xfd, 0x79, 0x02,
+	0x24, 0x9e, 0x80, 0x2b, 0x9e, 0x82, 0x87, 0xe0, 0x06, 0x09, 0x71, 0x07, 0x17, 0x08, 0x2d, 0xf7,
+	0x5c, 0xf0, 0x04, 0xc8, 0xe3, 0x19, 0xe7, 0xa7, 0xe9, 0xb2, 0xe2, 0xe7, 0xaa, 0x99, 0xef, 0xfb,
+	0xe6, 0xf3, 0x39, 0x67, 0x8e, 0x8f, 0xa7, 0xf0, 0x4e, 0xcf, 0x61, 0x27, 0x51, 0xb7, 0x64, 0x79,
+	0x83, 0x72, 0xe7, 0x04, 0x3b, 0x27, 0x8e, 0xdb, 0x0b, 0x9b, 0xc8, 0xce, 0xbd, 0xe0, 0xb4, 0xcc,
+	0x98, 0x5b, 0xa6, 0xbe, 0x53, 0x0e, 0xbc, 0x88, 0x61, 0x20, 0xfe, 0x94, 0xfc, 0xc0, 0x63, 0x1e,
+	0xc9, 0x24, 0xab, 0xe2, 0x46, 0xcf, 0xf3, 0x7a, 0x7d, 0x2c, 0x73, 0xb4, 0x1b, 0x1d, 0x97, 0x71,
+	0xe0, 0xb3, 0xcb, 0x44, 0x54, 0x7c, 0x30, 0xe2, 0xde, 0xf3, 0x7a, 0xde, 0x50, 0x15, 0xaf, 0xf8,
+	0x82, 0xff, 0x12, 0xf2, 0x65, 0xf9, 0x40, 0xea, 0x3b, 0x02, 0xda, 0x92, 0x10, 0x5f, 0x5a, 0x5e,
+	0x3f, 0xfd, 0x21, 0x04, 0x77, 0xa5, 0xa0, 0x47, 0x19, 0x9e, 0xd3, 0x4b, 0xf9, 0x37, 0xa1, 0x75,
+	0x02, 0x6a, 0x3b, 0xea, 0x86, 0x56, 0xe0, 0x74, 0xd1, 0xc0, 0x2f, 0x23, 0x0c, 0x99, 0xfe, 0xb3,
+	0x02, 0x85, 0xa7, 0x7e, 0xdf, 0x71, 0x4f, 0x1b, 0x18, 0x86, 0xb4, 0x87, 0x44, 0x83, 0x79, 0x9f,
+	0x5e, 0xf6, 0x3d, 0x6a, 0x6b, 0xca, 0xb6, 0xb2, 0x93, 0x37, 0xe4, 0x92, 0xdc, 0x87, 0xf9, 0x41,
+	0x22, 0xd2, 0x66, 0xb6, 0x95, 0x9d, 0x85, 0xdd, 0xe5, 0x52, 0x1a, 0x80, 0xd8, 0x6d, 0x48, 0x05,
+	0xa9, 0xc0, 0xb2, 0x24, 0xcd, 0x01, 0x32, 0x6a, 0x53, 0x46, 0xb5, 0x05, 0xbe, 0x6d, 0x75, 0xb8,
+	0xcd, 0xb8, 0x68, 0x08, 0xce, 0x50, 0x25, 0x28, 0x11, 0xf2, 0x21, 0xa8, 0x22, 0x81, 0xa1, 0x43,
+	0x9e, 0x3b, 0xac, 0x94, 0x64, 0x66, 0x23, 0x06, 0x4b, 0x02, 0x93, 0x80, 0xfe, 0xdd, 0x0c, 0x2c,
+	0xed, 0x7b, 0xe7, 0xee, 0xff, 0x90, 0xdd, 0x11, 0xdc, 0x4a, 0xb3, 0xb3, 0x3c, 0xf7, 0xd8, 0xe9,
+	0x45, 0x01, 0x65, 0x8e, 0xe7, 0x8a, 0x14, 0x6f, 0x0f, 0xf7, 0x76, 0x2e, 0xaa, 0xa3, 0x02, 0x63,
+	0x4d, 0x32, 0x63, 0x30, 0x69, 0xc0, 0x9a, 0x4c, 0x76, 0xdc, 0x30, 0xc9, 0x58, 0x4b, 0x33, 0x9e,
+	0xf4, 0x5b, 0x15, 0xc4, 0xb8, 0xdd, 0x26, 0x80, 0x63, 0xa3, 0xcb, 0x9c, 0x63, 0x07, 0x03, 0x6d,
+	0x6d, 0x5b, 0xd9, 0xc9, 0x19, 0x23, 0x08, 0xb9, 0x0d, 0x59, 0x16, 0x50, 0x0b, 0x4d, 0xc7, 0xd6,
+	0xb6, 0x38, 0x3b, 0xcf, 0xd7, 0x87, 0xb6, 0xfe, 0xd3, 0x2c, 0xac, 0xef, 0xe3, 0x99, 0x63, 0x61,
+	0xc5, 0x62, 0xce, 0x59, 0xf2, 0x94, 0xa4, 0x5d, 0xfe, 0xab, 0xf2, 0x35, 0x61, 0xde, 0xc6, 0x33,
+	0x13, 0x23, 0x87, 0xd7, 0x2b, 0xbf, 0xf7, 0xe8, 0x97, 0x5f, 0xb7, 0x1e, 0xfe, 0xdd, 0xeb, 0x67,
+	0x79, 0x01, 0x96, 0xd9, 0xa5, 0x8f, 0x61, 0x69, 0x1f, 0xcf, 0x6a, 0x4f, 0x0f, 0x8d, 0x8c, 0x8d,
+	0x67, 0xb5, 0xc8, 0x89, 0xfd, 0xa8, 0xef, 0x73, 0xbf, 0xfc, 0x3f, 0xf2, 0xab, 0xf8, 0x3e, 0xf7,
+	0xa3, 0xbe, 0x1f, 0xfb, 0x4d, 0x6d, 0xde, 0xb5, 0x7f, 0xdd, 0xbc, 0xb7, 0x5e, 0xbe, 0x79, 0x49,
+	0x03, 0x56, 0x68, 0x5a, 0xfe, 0xa1, 0xc5, 0x3a, 0xb7, 0xb8, 0x33, 0x0c, 0x62, 0x78, 0x46, 0xa9,
+	0x17, 0xa1, 0x57, 0x30, 0xbd, 0x08, 0xda, 0xd5, 0x33, 0x0d, 0x7d, 0xcf, 0x0d, 0x51, 0x7f, 0x04,
+	0xab, 0x07, 0xc9, 0xd3, 0xdb, 0x8c, 0xb2, 0x28, 0x94, 0x87, 0x7d, 0x17, 0x40, 0xa6, 0xe0, 0x24,
+	0xe7, 0x9d, 0x33, 0x72, 0x02, 0x39, 0xb4, 0xf5, 0x6f, 0x15, 0x58, 0x9b, 0xd8, 0x97, 0x18, 0x92,
+	0x0d, 0xc8, 0xf5, 0x69, 0xc8, 0xcc, 0x10, 0xd1, 0xe5, 0xfb, 0x66, 0x8d, 0x6c, 0x0c, 0xb4, 0x11,
+	0x5d, 0x72, 0x0f, 0x32, 0x21, 0x97, 0x8b, 0x3e, 0x59, 0x4a, 0xcb, 0x21, 0x5c, 0x04, 0xcd, 0x5d,
+	0x90, 0xda, 0x26, 0x73, 0x06, 0xa8, 0xcd, 0x0a, 0x17, 0xa4, 0x76, 0xc7, 0x19, 0x20, 0xd1, 0xa1,
+	0x30, 0xa0, 0x17, 0xe6, 0x50, 0x70, 0x93, 0x0b, 0x16, 0x06, 0xf4, 0xa2, 0x2e, 0x34, 0xfa, 0x12,
+	0x14, 0xc6, 0x12, 0xd2, 0xff, 0x98, 0x81, 0x4c, 0x82, 0x90, 0x1d, 0xc8, 0x84, 0x97, 0x21, 0xc3,
+	0x01, 0x8f, 0x6f, 0x61, 0x57, 0x2d, 0xc5, 0x73, 0xb6, 0xcd, 0xa1, 0x58, 0x12, 0x87, 0xc1, 0x17,
+	0xe4, 0x21, 0xe4, 0x2c, 0x6f, 0xe0, 0x7b, 0x2e, 0xba, 0x4c, 0x84, 0xbc, 0xc2, 0xc5, 0x55, 0x89,
+	0x26, 0xfa, 0xa1, 0x8a, 0x3c, 0x84, 0x45, 0x59, 0x38, 0x91, 0x6a, 0x32, 0x15, 0x80, 0xef, 0x33,
+	0x28, 0xc3, 0xd0, 0x28, 0xf4, 0x46, 0x4b, 0x47, 0x74, 0xc8, 0x44, 0x7c, 0x0c, 0x6b, 0xf9, 0x2b,
+	0x52, 0xc1, 0x90, 0xd7, 0x20, 0x6b, 0x8b, 0x71, 0xa6, 0x15, 0xae, 0xa8, 0x52, 0x8e, 0xbc, 0x01,
+	0x0b, 0xc3, 0x0e, 0x08, 0xb5, 0xc5, 0x2b, 0xd2, 0x51, 0x9a, 0x3c, 0x00, 0x62, 0x79, 0xae, 0x8b,
+	0x16, 0x43, 0xdb, 0x14, 0x41, 0x85, 0xbc, 0xd9, 0x0b, 0xc6, 0x72, 0xca, 0x88, 0x83, 0x0e, 0xc9,
+	0x7d, 0x18, 0x82, 0x66, 0x37, 0xf0, 0x4e, 0x31, 0x08, 0x79, 0x63, 0x17, 0x0c, 0x35, 0x25, 0xf6,
+	0x12, 0x5c, 0xff, 0x04, 0xd6, 0xe5, 0x00, 0x6e, 0xd3, 0x63, 0x6c, 0x78, 0xb6, 0xfc, 0xf0, 0xc4,
+	0x8f, 0x95, 0x01, 0x87, 0xa6, 0xed, 0x84, 0xb4, 0xdb, 0xc7, 0xa4, 0xc9, 0xb2, 0xc6, 0x72, 0xca,
+	0xec, 0x0b, 0x42, 0x5f, 0x83, 0x95, 0x2a, 0xf5, 0x69, 0xd7, 0xe9, 0x3b, 0xcc, 0xc1, 0xf4, 0x44,
+	0x23, 0xc8, 0x8f, 0xc2, 0xe4, 0x1e, 0x2c, 0x1d, 0x07, 0x31, 0xe7, 0x5a, 0x97, 0xa6, 0xdf, 0xa7,
+	0x6e, 0xa8, 0x29, 0xdb, 0xb3, 0x3b, 0x39, 0x63, 0x31, 0x85, 0x8f, 0x62, 0x94, 0x6c, 0xc3, 0xc2,
+	0xc0, 0xb3, 0xa3, 0xbe, 0xa8, 0xd1, 0x0c, 0x17, 0x8d, 0x42, 0xa4, 0x08, 0xd9, 0x63, 0xa4, 0x2c,
+	0x0a, 0x30, 0xd4, 0x66, 0x39, 0x9d, 0xae, 0xf5, 0xc7, 0xb0, 0x51, 0xed, 0x23, 0x0d, 0x64, 0xfb,
+	0x5b, 0x27, 0x68, 0x47, 0x7d, 0x7c, 0xc9, 0x17, 0xe7, 0x5d, 0xb8, 0x33, 0x7d, 0xb7, 0x78, 0x7d,
+	0x34, 0x98, 0xb7, 0x62, 0x5e, 0xd4, 0xa3, 0x60, 0xc8, 0xa5, 0xfe, 0xa7, 0x02, 0x6a, 0xe7, 0xa2,
+	0x62, 0x9d, 0xba, 0xde, 0x79, 0x1f, 0xed, 0xde, 0x20, 0xee, 0xb6, 0xf1, 0x51, 0xaf, 0x5c, 0x19,
+	0xf5, 0x6f, 0xc3, 0x1c, 0x06, 0x81, 0x17, 0xf0, 0xe6, 0x5d, 0xdc, 0xdd, 0x2c, 0x89, 0xbb, 0xcb,
+	0xa4, 0x51, 0xa9, 0x16, 0xab, 0x8c, 0x44, 0xac, 0x7f, 0xa5, 0xc0, 0x1c, 0x07, 0x48, 0x16, 0x6e,
+	0x36, 0x5b, 0xcd, 0x9a, 0x7a, 0x83, 0xe4, 0x21, 0xdb, 0x69, 0xb5, 0xcc, 0x7a, 0xa5, 0x53, 0x53,
+	0x15, 0x52, 0x80, 0x5c, 0xbc, 0xaa, 0x55, 0x8c, 0xfa, 0xe7, 0xea, 0x0c, 0x59, 0x05, 0xb5, 0xda,
+	0xaa, 0xd7, 0x0f, 0xdb, 0x87, 0xad, 0xa6, 0x79, 0x54, 0xa9, 0x3e, 0xa9, 0x75, 0xd4, 0xd9, 0x71,
+	0x74, 0xaf, 0x56, 0xa9, 0xb6, 0x9a, 0xea, 0x4d, 0xb2, 0x00, 0xf3, 0x9d, 0x67, 0xe6, 0xc7, 0x46,
+	0xed, 0x53, 0x75, 0x8e, 0xbb, 0x3e, 0x33, 0x8f, 0x5a, 0x9f, 0xd5, 0x0c, 0x35, 0x43, 0x54, 0xc8,
+	0x1f, 0x1c, 0xb5, 0xcd, 0xa7, 0xcd, 0x7a, 0xab, 0xfa, 0xa4, 0xb6, 0xaf, 0xce, 0xef, 0xfe, 0x30,
+	0x03, 0x19, 0x83, 0x87, 0x4c, 0xde, 0x87, 0xc2, 0xd8, 0xc4, 0x21, 0x93, 0xc3, 0xa3, 0x78, 0xab,
+	0x94, 0xdc, 0xc1, 0x4a, 0xf2, 0x76, 0x55, 0xaa, 0xc5, 0x77, 0xb0, 0x1d, 0x85, 0xbc, 0x07, 0x99,
+	0xe4, 0xa2, 0x43, 0xd6, 0x64, 0x05, 0xc6, 0x2e, 0x3e, 0x2f, 0xd8, 0xfa, 0x11, 0xe4, 0xd2, 0x8b,
+	0x13, 0xd1, 0xe4, 0xee, 0xc9, 0xbb, 0x54, 0x71, 0x5d, 0x32, 0x13, 0x97, 0x8e, 0x37, 0x15, 0xd2,
+	0x80, 0xac, 0x18, 0xbc, 0x48, 0xb6, 0x52, 0xd9, 0xf4, 0x8f, 0x6c, 0x71, 0xfb, 0x7a, 0x81, 0xe8,
+	0x90, 0x0f, 0x60, 0x8e, 0x9f, 0xde, 0x30, 0x98, 0xc9, 0xc3, 0xbc, 0x3e, 0x9b, 0xdd, 0xaf, 0x67,
+	0xa1, 0x90, 0xd4, 0xb3, 0x41, 0x5d, 0xda, 0xc3, 0x80, 0xd4, 0x27, 0xcb, 0x7a, 0x47, 0xda, 0x4e,
+	0xfb, 0x2e, 0x14, 0xef, 0x5e, 0xc3, 0x8a, 0xe0, 0x76, 0x21, 0x77, 0x80, 0x4c, 0x38, 0xa5, 0xb5,
+	0x1e, 0xb7, 0x58, 0x1c, 0x87, 0x49, 0x13, 0x56, 0xda, 0xc8, 0x26, 0x67, 0xc5, 0x48, 0xa9, 0xa6,
+	0x4f, 0x91, 0xeb, 0xb2, 0x24, 0xfb, 0xb0, 0x74, 0x80, 0x6c, 0x6c, 0x34, 0x6c, 0x48, 0xaf, 0x29,
+	0x73, 0xa4, 0xb8, 0x3a, 0x8d, 0x24, 0x14, 0x56, 0xa7, 0xbd, 0xa8, 0xe4, 0x95, 0x54, 0x7d, 0xfd,
+	0x10, 0x28, 0xbe, 0xfa, 0x62, 0x51, 0x52, 0xac, 0xbd, 0xc7, 0xdf, 0x3f, 0xdf, 0x54, 0x7e, 0x7c,
+	0xbe, 0xa9, 0xfc, 0xf6, 0x7c, 0x53, 0xf9, 0xe6, 0xf7, 0xcd, 0x1b, 0x5f, 0xbc, 0xfe, 0xf2, 0xff,
+	0x85, 0x74, 0x33, 0x3c, 0xed, 0xb7, 0xfe, 0x1a, 0x00, 0xe4, 0x4a, 0xb1, 0xae, 0xba, 0x0c, 0x00,
+	0x00,
 }
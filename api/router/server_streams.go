@@ -20,6 +20,7 @@ type RouterStreamServer struct {
 	UplinkChanFunc        func(md metadata.MD) (ch chan *UplinkMessage, err error)
 	GatewayStatusChanFunc func(md metadata.MD) (ch chan *gateway.Status, err error)
 	DownlinkChanFunc      func(md metadata.MD) (ch <-chan *DownlinkMessage, cancel func(), err error)
+	TxAckChanFunc         func(md metadata.MD) (ch chan *TxAcknowledgment, err error)
 }
 
 // NewRouterStreamServer returns a new RouterStreamServer
@@ -117,3 +118,31 @@ func (s *RouterStreamServer) GatewayStatus(stream Router_GatewayStatusServer) er
 		ch <- status
 	}
 }
+
+// TxAck handles TxAck streams
+func (s *RouterStreamServer) TxAck(stream Router_TxAckServer) error {
+	md, err := api.MetadataFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	ch, err := s.TxAckChanFunc(md)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		close(ch)
+	}()
+	for {
+		ack, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&empty.Empty{})
+		}
+		if err != nil {
+			return err
+		}
+		if err := ack.Validate(); err != nil {
+			return errors.Wrap(err, "Invalid TxAck")
+		}
+		ch <- ack
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import "fmt"
+
+// TxError is the error of a Gateway that did not transmit a DownlinkMessage
+type TxError struct {
+	Reason TxAcknowledgment_Error
+}
+
+func (e *TxError) Error() string {
+	return fmt.Sprintf("Gateway did not transmit downlink: %s", e.Reason)
+}
+
+// Err returns a *TxError if the Gateway did not transmit the DownlinkMessage
+func (m *TxAcknowledgment) Err() error {
+	if m.Error == TxAcknowledgment_NONE {
+		return nil
+	}
+	return &TxError{Reason: m.Error}
+}
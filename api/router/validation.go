@@ -52,3 +52,11 @@ func (m *DeviceActivationRequest) Validate() error {
 	}
 	return nil
 }
+
+// Validate implements the api.Validator interface
+func (m *TxAcknowledgment) Validate() error {
+	if m.Identifier == "" {
+		return errors.NewErrInvalidArgument("Identifier", "can not be empty")
+	}
+	return nil
+}
@@ -57,6 +57,8 @@ var brokerCmd = &cobra.Command{
 			time.Duration(viper.GetInt("broker.deduplication-delay")) * time.Millisecond,
 		)
 		broker.SetNetworkServer(viper.GetString("broker.networkserver-address"), nsCert, viper.GetString("broker.networkserver-token"))
+		broker.SetKeepGatewayDuplicates(viper.GetBool("broker.keep-gateway-duplicates"))
+		broker.SetDownlinkOptionsSort(viper.GetString("broker.downlink-options-sort"))
 		err = broker.Init(component)
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize broker")
@@ -96,6 +98,10 @@ func init() {
 
 	brokerCmd.Flags().Int("deduplication-delay", 200, "Deduplication delay (in ms)")
 	viper.BindPFlag("broker.deduplication-delay", brokerCmd.Flags().Lookup("deduplication-delay"))
+	brokerCmd.Flags().Bool("keep-gateway-duplicates", false, "Keep duplicate uplinks that are reported by the same gateway, instead of only the copy with the best metadata")
+	viper.BindPFlag("broker.keep-gateway-duplicates", brokerCmd.Flags().Lookup("keep-gateway-duplicates"))
+	brokerCmd.Flags().String("downlink-options-sort", "", "The order in which routers should send downlink options (score, window or airtime)")
+	viper.BindPFlag("broker.downlink-options-sort", brokerCmd.Flags().Lookup("downlink-options-sort"))
 
 	brokerCmd.Flags().String("server-address", "0.0.0.0", "The IP address to listen for communication")
 	brokerCmd.Flags().String("server-address-announce", "localhost", "The public IP address to announce")
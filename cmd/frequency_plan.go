@@ -0,0 +1,17 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+// frequencyPlanCmd represents the frequency-plan command
+var frequencyPlanCmd = &cobra.Command{
+	Use:   "frequency-plan",
+	Short: "Manage frequency plan overrides",
+	Long:  `ttn frequency-plan can be used to manage the files that override the frequency plans of regions`,
+}
+
+func init() {
+	RootCmd.AddCommand(frequencyPlanCmd)
+}
@@ -0,0 +1,50 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/spf13/cobra"
+)
+
+// frequencyPlanValidateCmd represents the frequency-plan validate command
+var frequencyPlanValidateCmd = &cobra.Command{
+	Use:   "validate [region] [file]",
+	Short: "Validate a frequency plan override",
+	Long:  `ttn frequency-plan validate checks a file that overrides the frequency plan of a region, as it is loaded by the --frequency-plans option of the router`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			cmd.UsageFunc()(cmd)
+			return
+		}
+
+		ctx := ctx.WithField("Region", args[0]).WithField("File", args[1])
+
+		plan, err := band.Get(args[0])
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get frequency plan of region")
+		}
+
+		override, err := band.ReadFrequencyPlanOverride(args[1])
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not read frequency plan override")
+		}
+
+		errs := override.Validate(plan)
+		for _, err := range errs {
+			ctx.WithError(err).Error("Invalid frequency plan override")
+		}
+		if len(errs) != 0 {
+			os.Exit(1)
+		}
+
+		ctx.Info("Frequency plan override is valid")
+	},
+}
+
+func init() {
+	frequencyPlanCmd.AddCommand(frequencyPlanValidateCmd)
+}
@@ -39,7 +39,10 @@ var routerCmd = &cobra.Command{
 		}
 
 		// Router
-		router := router.NewRouter()
+		router, err := router.NewRouter()
+		if err != nil {
+			ctx.WithError(err).Fatal("Invalid router configuration")
+		}
 		err = router.Init(component)
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize router")
@@ -73,8 +76,64 @@ func init() {
 	routerCmd.Flags().String("server-address-announce", "localhost", "The public IP address to announce")
 	routerCmd.Flags().Int("server-port", 1901, "The port for communication")
 	routerCmd.Flags().Bool("skip-verify-gateway-token", false, "Skip verification of the gateway token")
+	routerCmd.Flags().Int("downlink-stickiness", 0, "Score bonus for the gateway that was used for the previous downlink to a device (0 disables)")
+	routerCmd.Flags().Int("min-tx-power", 0, "The minimum conducted TX power (in dBm) of gateways")
+	routerCmd.Flags().StringSlice("rx-only-gateways", []string{}, "IDs of gateways that can not transmit; their uplink is forwarded without downlink options")
+	routerCmd.Flags().String("gateway-attributes", "", "File with the antenna gain, cable loss, full duplex capability, maximum RX1 data rate and duty cycle overrides of gateways")
+	routerCmd.Flags().Int("tx-power-error-threshold", 3, "The number of TX power errors after which the maximum TX power of a gateway is lowered (0 to disable)")
+	routerCmd.Flags().StringSlice("sub-bands", []string{}, "Active sub-bands for regions that have sub-bands (for example US_902_928=2)")
+	routerCmd.Flags().StringSlice("frequency-plans", []string{}, "Files that override the duty cycle, dwell time and RX2 data rate of the frequency plans of regions (for example EU_863_870=eu.yml)")
+	routerCmd.Flags().StringSlice("rx1-dr-offset", []string{}, "RX1 data rate offset for regions (for example EU_863_870=1)")
+	routerCmd.Flags().StringSlice("tx-power-index", []string{}, "Default TX power index of downlink for regions, where 0 is the maximum EIRP (for example EU_863_870=1)")
+	routerCmd.Flags().StringSlice("rx2-fallback-frequencies", []string{}, "RX2 frequencies to use if the default RX2 frequency is saturated (for example EU_863_870=869700000)")
+	routerCmd.Flags().StringSlice("forbidden-frequencies", []string{}, "Frequencies on which transmissions are forbidden (for example EU_863_870=868300000)")
+	routerCmd.Flags().Int("max-acceptable-score", 0, "The maximum score of downlink options; options with a higher score are dropped (0 disables)")
+	routerCmd.Flags().Duration("network-airtime-quota", 0, "The maximum downlink airtime per hour of each network (0 disables)")
+	routerCmd.Flags().StringSlice("duty-cycle-groups", []string{}, "Groups of gateways that share their duty cycle (for example eui-0102030405060708=site-1)")
+	routerCmd.Flags().StringSlice("priority-class-quotas", []string{}, "Share of the downlink airtime of a gateway that a priority class may use (for example bulk=0.002)")
+	routerCmd.Flags().StringSlice("priority-class-fports", []string{}, "FPorts of the downlinks that belong to a priority class (for example bulk=200)")
+	routerCmd.Flags().Float64("jitter-guard-factor", 0, "Factor by which the guard between transmissions of a gateway scales with the measured precision of its timestamps (0 disables)")
+	routerCmd.Flags().StringSlice("timestamp-precision", []string{}, "Precision of the timestamps (in µs) of gateways per platform (format: platform=precision)")
+	routerCmd.Flags().Duration("rx-tx-switch-guard", 0, "The time gateways need to switch from RX to TX after an uplink")
+	routerCmd.Flags().Int("txack-bonus", 0, "Score bonus for gateways that acknowledged all their recent downlinks (0 disables)")
+	routerCmd.Flags().Int("rx2-power-floor", 0, "The minimum TX power (in dBm) of RX2 downlink (0 disables)")
+	routerCmd.Flags().Float64("snr-dominance", 0, "Weight of SNR versus RSSI in the signal score of downlink options, between -1 (only RSSI) and 1 (only SNR)")
+	routerCmd.Flags().Bool("rescore-downlink", false, "Re-score the downlink options of all gateways that received the uplink when handling downlink")
+	routerCmd.Flags().Bool("downlinks-disabled", false, "Start in safe mode, in which all downlinks are rejected")
+	routerCmd.Flags().Bool("always-rx2", false, "Also send downlinks in RX2 when they are sent in RX1")
+	routerCmd.Flags().Bool("downlink-attempts", false, "Schedule RX2 as a second attempt that is only sent if the gateway does not acknowledge RX1")
+	routerCmd.Flags().Bool("trace-frames", false, "Log the hex of all downlink frames (do not enable in production)")
+	routerCmd.Flags().Bool("gps-lost-suspends-class-b", true, "Suspend Class B downlink through gateways that lost their GPS lock")
 	viper.BindPFlag("router.server-address", routerCmd.Flags().Lookup("server-address"))
 	viper.BindPFlag("router.server-address-announce", routerCmd.Flags().Lookup("server-address-announce"))
 	viper.BindPFlag("router.server-port", routerCmd.Flags().Lookup("server-port"))
 	viper.BindPFlag("router.skip-verify-gateway-token", routerCmd.Flags().Lookup("skip-verify-gateway-token"))
+	viper.BindPFlag("router.downlink-stickiness", routerCmd.Flags().Lookup("downlink-stickiness"))
+	viper.BindPFlag("router.min-tx-power", routerCmd.Flags().Lookup("min-tx-power"))
+	viper.BindPFlag("router.rx-only-gateways", routerCmd.Flags().Lookup("rx-only-gateways"))
+	viper.BindPFlag("router.gateway-attributes", routerCmd.Flags().Lookup("gateway-attributes"))
+	viper.BindPFlag("router.tx-power-error-threshold", routerCmd.Flags().Lookup("tx-power-error-threshold"))
+	viper.BindPFlag("router.sub-bands", routerCmd.Flags().Lookup("sub-bands"))
+	viper.BindPFlag("router.frequency-plans", routerCmd.Flags().Lookup("frequency-plans"))
+	viper.BindPFlag("router.rx1-dr-offset", routerCmd.Flags().Lookup("rx1-dr-offset"))
+	viper.BindPFlag("router.tx-power-index", routerCmd.Flags().Lookup("tx-power-index"))
+	viper.BindPFlag("router.rx2-fallback-frequencies", routerCmd.Flags().Lookup("rx2-fallback-frequencies"))
+	viper.BindPFlag("router.forbidden-frequencies", routerCmd.Flags().Lookup("forbidden-frequencies"))
+	viper.BindPFlag("router.max-acceptable-score", routerCmd.Flags().Lookup("max-acceptable-score"))
+	viper.BindPFlag("router.network-airtime-quota", routerCmd.Flags().Lookup("network-airtime-quota"))
+	viper.BindPFlag("router.duty-cycle-groups", routerCmd.Flags().Lookup("duty-cycle-groups"))
+	viper.BindPFlag("router.priority-class-quotas", routerCmd.Flags().Lookup("priority-class-quotas"))
+	viper.BindPFlag("router.priority-class-fports", routerCmd.Flags().Lookup("priority-class-fports"))
+	viper.BindPFlag("router.jitter-guard-factor", routerCmd.Flags().Lookup("jitter-guard-factor"))
+	viper.BindPFlag("router.timestamp-precision", routerCmd.Flags().Lookup("timestamp-precision"))
+	viper.BindPFlag("router.rx-tx-switch-guard", routerCmd.Flags().Lookup("rx-tx-switch-guard"))
+	viper.BindPFlag("router.txack-bonus", routerCmd.Flags().Lookup("txack-bonus"))
+	viper.BindPFlag("router.rx2-power-floor", routerCmd.Flags().Lookup("rx2-power-floor"))
+	viper.BindPFlag("router.snr-dominance", routerCmd.Flags().Lookup("snr-dominance"))
+	viper.BindPFlag("router.rescore-downlink", routerCmd.Flags().Lookup("rescore-downlink"))
+	viper.BindPFlag("router.downlinks-disabled", routerCmd.Flags().Lookup("downlinks-disabled"))
+	viper.BindPFlag("router.always-rx2", routerCmd.Flags().Lookup("always-rx2"))
+	viper.BindPFlag("router.downlink-attempts", routerCmd.Flags().Lookup("downlink-attempts"))
+	viper.BindPFlag("router.trace-frames", routerCmd.Flags().Lookup("trace-frames"))
+	viper.BindPFlag("router.gps-lost-suspends-class-b", routerCmd.Flags().Lookup("gps-lost-suspends-class-b"))
 }
@@ -1,6 +1,11 @@
 package band
 
 import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/brocaar/lorawan"
@@ -11,31 +16,210 @@ import (
 type FrequencyPlan struct {
 	lora.Band
 	CFList *lorawan.CFList
+	// ChannelMask indicates which uplink channels are enabled. If it is empty,
+	// all uplink channels are enabled.
+	ChannelMask []bool
+	// Region of the frequency plan
+	Region string
+	// DutyCycle indicates whether the duty cycle of transmissions is limited
+	DutyCycle bool
+	// DwellTime is the maximum duration of a transmission. Zero means no limit.
+	DwellTime time.Duration
+	// RX1DROffset is the offset between the uplink data rate and the RX1 data rate
+	RX1DROffset int
+	// RX1WindowTolerance is how much later than the start of RX1 a downlink
+	// can be sent and still be received by devices. The devices of the region
+	// keep their RX1 window open for at least this long after its start.
+	RX1WindowTolerance time.Duration
+	// MaxEIRP is the maximum EIRP (in dBm) of the region
+	MaxEIRP float64
+	// RX2MaxEIRP is the maximum EIRP (in dBm) on the RX2 frequency if the
+	// region allows a different power there. Zero means MaxEIRP.
+	RX2MaxEIRP float64
+	// MaxTXPowerIndex is the highest TX power index of the region
+	MaxTXPowerIndex int
+	// DefaultTXPowerIndex is the TX power index of downlink. Index 0 is the
+	// MaxEIRP of the region.
+	DefaultTXPowerIndex int
+}
+
+// Regions with sub-bands have 64 125 kHz uplink channels and 8 500 kHz uplink
+// channels. Each of the 8 sub-bands consists of 8 125 kHz channels and one 500
+// kHz channel.
+const (
+	numSubBands        = 8
+	subBandChannels    = 8
+	subBandNumChannels = numSubBands*subBandChannels + numSubBands
+)
+
+// SetSubBands updates the channel mask so that only the uplink channels of the
+// given sub-bands (1-8) are enabled
+func (f *FrequencyPlan) SetSubBands(subBands ...int) error {
+	if len(f.UplinkChannels) != subBandNumChannels {
+		return errors.NewErrInvalidArgument("Sub-bands", "not supported in this band")
+	}
+	mask := make([]bool, subBandNumChannels)
+	for _, subBand := range subBands {
+		if subBand < 1 || subBand > numSubBands {
+			return errors.NewErrInvalidArgument("Sub-band", fmt.Sprintf("%d out of range", subBand))
+		}
+		for i := 0; i < subBandChannels; i++ {
+			mask[(subBand-1)*subBandChannels+i] = true
+		}
+		mask[numSubBands*subBandChannels+subBand-1] = true
+	}
+	f.ChannelMask = mask
+	return nil
+}
+
+// GetUplinkChannel returns the number of the uplink channel with the given frequency
+func (f *FrequencyPlan) GetUplinkChannel(frequency int) (int, error) {
+	for i, channel := range f.UplinkChannels {
+		if channel.Frequency == frequency {
+			return i, nil
+		}
+	}
+	return 0, errors.NewErrNotFound(fmt.Sprintf("Uplink channel for frequency %d", frequency))
+}
+
+// GetRX1Frequency returns the RX1 frequency for an uplink on the given
+// frequency. It returns an error if the uplink channel is disabled in the
+// channel mask.
+func (f *FrequencyPlan) GetRX1Frequency(frequency int) (int, error) {
+	if len(f.ChannelMask) > 0 {
+		channel, err := f.GetUplinkChannel(frequency)
+		if err != nil {
+			return 0, err
+		}
+		if channel >= len(f.ChannelMask) || !f.ChannelMask[channel] {
+			return 0, errors.NewErrInvalidArgument("Uplink channel", fmt.Sprintf("%d disabled in channel mask", channel))
+		}
+	}
+	return f.Band.GetRX1Frequency(frequency)
+}
+
+// GetRX1DataRate returns the RX1 data rate for the given uplink data rate,
+// using the RX1DROffset of the frequency plan. If the offset is too large for
+// the uplink data rate, it is clamped to the lowest legal RX1 data rate.
+func (f *FrequencyPlan) GetRX1DataRate(uplinkDR int) (int, error) {
+	offset, err := f.rx1DROffset(uplinkDR)
+	if err != nil {
+		return 0, err
+	}
+	return f.Band.GetRX1DataRate(uplinkDR, offset)
 }
 
-// Guess the region based on frequency
+// GetRX1DataRates returns the legal RX1 data rates for the given uplink data
+// rate that are not faster than the one of GetRX1DataRate, from fast to slow.
+// These are the data rates of the RX1DROffset and all larger offsets.
+func (f *FrequencyPlan) GetRX1DataRates(uplinkDR int) ([]int, error) {
+	offset, err := f.rx1DROffset(uplinkDR)
+	if err != nil {
+		return nil, err
+	}
+	return append([]int{}, f.RX1DataRate[uplinkDR][offset:]...), nil
+}
+
+// rx1DROffset returns the RX1DROffset of the frequency plan, clamped to the
+// offsets that are legal for the uplink data rate
+func (f *FrequencyPlan) rx1DROffset(uplinkDR int) (int, error) {
+	if uplinkDR < 0 || uplinkDR >= len(f.RX1DataRate) {
+		return 0, errors.NewErrInvalidArgument("Uplink data rate", fmt.Sprintf("%d is not valid", uplinkDR))
+	}
+	offset := f.RX1DROffset
+	if offset < 0 {
+		offset = 0
+	}
+	if max := len(f.RX1DataRate[uplinkDR]) - 1; offset > max {
+		offset = max
+	}
+	return offset, nil
+}
+
+// DownlinkFrequencies returns all frequencies (in Hz) that are used for
+// downlink in the frequency plan, in ascending order. These are the RX1
+// frequencies and the RX2 frequency.
+func (f *FrequencyPlan) DownlinkFrequencies() []uint64 {
+	unique := make(map[uint64]bool)
+	for _, channel := range f.DownlinkChannels {
+		unique[uint64(channel.Frequency)] = true
+	}
+	unique[uint64(f.RX2Frequency)] = true
+	frequencies := make([]uint64, 0, len(unique))
+	for frequency := range unique {
+		frequencies = append(frequencies, frequency)
+	}
+	sort.Sort(frequencySlice(frequencies))
+	return frequencies
+}
+
+type frequencySlice []uint64
+
+func (s frequencySlice) Len() int           { return len(s) }
+func (s frequencySlice) Less(i, j int) bool { return s[i] < s[j] }
+func (s frequencySlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// GetTXPower returns the EIRP (in dBm) for the given TX power index. Each
+// index lowers the EIRP by 2 dB, starting from the MaxEIRP of the region.
+func (f *FrequencyPlan) GetTXPower(txPowerIndex int) (int32, error) {
+	if txPowerIndex < 0 || txPowerIndex > f.MaxTXPowerIndex {
+		return 0, errors.NewErrInvalidArgument("TX power index", fmt.Sprintf("%d is not valid", txPowerIndex))
+	}
+	return int32(math.Floor(f.MaxEIRP - 2*float64(txPowerIndex))), nil
+}
+
+// GetRX2TXPower returns the EIRP (in dBm) on the RX2 frequency for the given TX
+// power index
+func (f *FrequencyPlan) GetRX2TXPower(txPowerIndex int) (int32, error) {
+	if f.RX2MaxEIRP == 0 {
+		return f.GetTXPower(txPowerIndex)
+	}
+	if txPowerIndex < 0 || txPowerIndex > f.MaxTXPowerIndex {
+		return 0, errors.NewErrInvalidArgument("TX power index", fmt.Sprintf("%d is not valid", txPowerIndex))
+	}
+	return int32(math.Floor(f.RX2MaxEIRP - 2*float64(txPowerIndex))), nil
+}
+
+// Guess the region based on frequency. If the frequency is used in multiple
+// regions, the first region returned by GuessAll is used.
 func Guess(frequency uint64) string {
-	switch {
-	case frequency >= 863000000 && frequency <= 870000000:
-		return pb_lorawan.Region_EU_863_870.String()
-	case frequency >= 902300000 && frequency <= 914900000:
-		return pb_lorawan.Region_US_902_928.String()
-	case frequency >= 779500000 && frequency <= 786500000:
-		return pb_lorawan.Region_CN_779_787.String()
-	case frequency >= 433175000 && frequency <= 434665000:
-		return pb_lorawan.Region_EU_433.String()
-	case frequency == 923200000 || frequency == 923400000:
-		return pb_lorawan.Region_AS_923.String()
-	case frequency >= 920900000 || frequency == 923300000:
-		return pb_lorawan.Region_KR_920_923.String()
-	case frequency >= 915200000 && frequency <= 927800000:
-		return pb_lorawan.Region_AU_915_928.String()
-	case frequency >= 470300000 && frequency <= 489300000:
-		return pb_lorawan.Region_CN_470_510.String()
+	if regions := GuessAll(frequency); len(regions) > 0 {
+		return regions[0]
 	}
 	return ""
 }
 
+// GuessAll returns all regions that use the given frequency, in a fixed order
+func GuessAll(frequency uint64) (regions []string) {
+	if frequency >= 863000000 && frequency <= 870000000 {
+		regions = append(regions, pb_lorawan.Region_EU_863_870.String())
+	}
+	if frequency >= 902300000 && frequency <= 914900000 {
+		regions = append(regions, pb_lorawan.Region_US_902_928.String())
+	}
+	if frequency >= 779500000 && frequency <= 786500000 {
+		regions = append(regions, pb_lorawan.Region_CN_779_787.String())
+	}
+	if frequency >= 433175000 && frequency <= 434665000 {
+		regions = append(regions, pb_lorawan.Region_EU_433.String())
+	}
+	if frequency == 923200000 || frequency == 923400000 {
+		regions = append(regions, pb_lorawan.Region_AS_923.String())
+	}
+	// This is the range that Guess has always used for KR_920_923, which also
+	// includes the AU_915_928 frequencies above 920.9 MHz
+	if frequency >= 920900000 || frequency == 923300000 {
+		regions = append(regions, pb_lorawan.Region_KR_920_923.String())
+	}
+	if frequency >= 915200000 && frequency <= 927800000 {
+		regions = append(regions, pb_lorawan.Region_AU_915_928.String())
+	}
+	if frequency >= 470300000 && frequency <= 489300000 {
+		regions = append(regions, pb_lorawan.Region_CN_470_510.String())
+	}
+	return
+}
+
 // Get the frequency plan for the given region
 func Get(region string) (frequencyPlan FrequencyPlan, err error) {
 	switch region {
@@ -57,25 +241,44 @@ func Get(region string) (frequencyPlan FrequencyPlan, err error) {
 		}
 		frequencyPlan.DownlinkChannels = frequencyPlan.UplinkChannels
 		frequencyPlan.CFList = &lorawan.CFList{867100000, 867300000, 867500000, 867700000, 867900000}
+		frequencyPlan.DutyCycle = true
+		frequencyPlan.RX1WindowTolerance = 5 * time.Millisecond
+		frequencyPlan.MaxEIRP, frequencyPlan.MaxTXPowerIndex = 16, 7
+		// The RX2 frequency allows 500 mW ERP (27 dBm ERP is 29.15 dBm EIRP)
+		frequencyPlan.RX2MaxEIRP = 29.15
 	case pb_lorawan.Region_US_902_928.String():
 		frequencyPlan.Band, err = lora.GetConfig(lora.US_902_928, false, lorawan.DwellTime400ms)
+		// RX1 uses 500 kHz channels, of which the symbols are 4 times shorter
+		frequencyPlan.RX1WindowTolerance = 2 * time.Millisecond
+		frequencyPlan.MaxEIRP, frequencyPlan.MaxTXPowerIndex = 30, 10
 	case pb_lorawan.Region_CN_779_787.String():
 		err = errors.NewErrInternal("China 779-787 MHz band not supported")
 	case pb_lorawan.Region_EU_433.String():
 		err = errors.NewErrInternal("Europe 433 MHz band not supported")
 	case pb_lorawan.Region_AU_915_928.String():
 		frequencyPlan.Band, err = lora.GetConfig(lora.AU_915_928, false, lorawan.DwellTime400ms)
+		// RX1 uses 500 kHz channels, of which the symbols are 4 times shorter
+		frequencyPlan.RX1WindowTolerance = 2 * time.Millisecond
+		frequencyPlan.MaxEIRP, frequencyPlan.MaxTXPowerIndex = 30, 10
 	case pb_lorawan.Region_CN_470_510.String():
 		frequencyPlan.Band, err = lora.GetConfig(lora.CN_470_510, false, lorawan.DwellTimeNoLimit)
+		frequencyPlan.RX1WindowTolerance = 5 * time.Millisecond
+		frequencyPlan.MaxEIRP, frequencyPlan.MaxTXPowerIndex = 19.15, 7
 	case pb_lorawan.Region_AS_923.String():
 		frequencyPlan.Band, err = lora.GetConfig(lora.AS_923, false, lorawan.DwellTime400ms)
+		frequencyPlan.DwellTime = 400 * time.Millisecond
+		frequencyPlan.RX1WindowTolerance = 5 * time.Millisecond
+		frequencyPlan.MaxEIRP, frequencyPlan.MaxTXPowerIndex = 16, 7
 	case pb_lorawan.Region_KR_920_923.String():
 		frequencyPlan.Band, err = lora.GetConfig(lora.KR_920_923, false, lorawan.DwellTimeNoLimit)
+		frequencyPlan.RX1WindowTolerance = 5 * time.Millisecond
+		frequencyPlan.MaxEIRP, frequencyPlan.MaxTXPowerIndex = 14, 7
 	default:
 		err = errors.NewErrInvalidArgument("Frequency Band", "unknown")
 	}
 	if err != nil {
 		return
 	}
+	frequencyPlan.Region = region
 	return
 }
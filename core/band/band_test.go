@@ -0,0 +1,123 @@
+package band
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestGuess(t *testing.T) {
+	a := New(t)
+
+	a.So(Guess(868100000), ShouldEqual, "EU_863_870")
+	a.So(Guess(904700000), ShouldEqual, "US_902_928")
+	a.So(Guess(100000000), ShouldBeEmpty)
+
+	// Overlapping frequencies are resolved in a fixed order
+	a.So(GuessAll(923200000), ShouldResemble, []string{"AS_923", "KR_920_923", "AU_915_928"})
+	a.So(Guess(923200000), ShouldEqual, "AS_923")
+	a.So(Guess(923300000), ShouldEqual, "KR_920_923")
+	a.So(Guess(916800000), ShouldEqual, "AU_915_928")
+
+	// Frequencies above 920.9 MHz are guessed as KR_920_923, like before
+	a.So(Guess(925000000), ShouldEqual, "KR_920_923")
+	a.So(GuessAll(925000000), ShouldResemble, []string{"KR_920_923", "AU_915_928"})
+	a.So(Guess(927500000), ShouldEqual, "KR_920_923")
+}
+
+func TestGetRX1DataRate(t *testing.T) {
+	a := New(t)
+
+	plan, _ := Get("EU_863_870")
+
+	dr, err := plan.GetRX1DataRate(5)
+	a.So(err, ShouldBeNil)
+	a.So(dr, ShouldEqual, 5)
+
+	plan.RX1DROffset = 2
+	dr, err = plan.GetRX1DataRate(5)
+	a.So(err, ShouldBeNil)
+	a.So(dr, ShouldEqual, 3)
+
+	// Offset is clamped to the lowest legal RX1 data rate
+	plan.RX1DROffset = 10
+	dr, err = plan.GetRX1DataRate(5)
+	a.So(err, ShouldBeNil)
+	a.So(dr, ShouldEqual, 0)
+	dr, err = plan.GetRX1DataRate(0)
+	a.So(err, ShouldBeNil)
+	a.So(dr, ShouldEqual, 0)
+
+	_, err = plan.GetRX1DataRate(20)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestGetRX1DataRates(t *testing.T) {
+	a := New(t)
+
+	plan, _ := Get("EU_863_870")
+	drs, err := plan.GetRX1DataRates(5)
+	a.So(err, ShouldBeNil)
+	a.So(drs, ShouldResemble, []int{5, 4, 3, 2, 1, 0})
+
+	plan.RX1DROffset = 2
+	drs, err = plan.GetRX1DataRates(5)
+	a.So(err, ShouldBeNil)
+	a.So(drs, ShouldResemble, []int{3, 2, 1, 0})
+
+	// The RX1 data rates of US_902_928 are the 500 kHz data rates DR8-13
+	plan, _ = Get("US_902_928")
+	drs, err = plan.GetRX1DataRates(4)
+	a.So(err, ShouldBeNil)
+	a.So(drs, ShouldResemble, []int{13, 13, 12, 11})
+
+	_, err = plan.GetRX1DataRates(20)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestGetTXPower(t *testing.T) {
+	a := New(t)
+
+	plan, _ := Get("EU_863_870")
+
+	for index, eirp := range []int32{16, 14, 12, 10, 8, 6, 4, 2} {
+		power, err := plan.GetTXPower(index)
+		a.So(err, ShouldBeNil)
+		a.So(power, ShouldEqual, eirp)
+	}
+
+	_, err := plan.GetTXPower(8)
+	a.So(err, ShouldNotBeNil)
+	_, err = plan.GetTXPower(-1)
+	a.So(err, ShouldNotBeNil)
+
+	plan, _ = Get("CN_470_510")
+	power, _ := plan.GetTXPower(1)
+	a.So(power, ShouldEqual, 17)
+
+	// The EU RX2 frequency allows more power
+	plan, _ = Get("EU_863_870")
+	for index, eirp := range []int32{29, 27, 25, 23, 21, 19, 17, 15} {
+		power, err := plan.GetRX2TXPower(index)
+		a.So(err, ShouldBeNil)
+		a.So(power, ShouldEqual, eirp)
+	}
+	_, err = plan.GetRX2TXPower(8)
+	a.So(err, ShouldNotBeNil)
+
+	// Other regions use MaxEIRP on the RX2 frequency
+	plan, _ = Get("US_902_928")
+	power, _ = plan.GetRX2TXPower(0)
+	a.So(power, ShouldEqual, 30)
+}
+
+func TestDownlinkFrequencies(t *testing.T) {
+	a := New(t)
+
+	plan, _ := Get("EU_863_870")
+	a.So(plan.DownlinkFrequencies(), ShouldResemble, []uint64{
+		867100000, 867300000, 867500000, 867700000, 867900000, // RX1
+		868100000, 868300000, 868500000, 868800000, // RX1
+		869525000, // RX2
+	})
+}
@@ -0,0 +1,71 @@
+package band
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// FrequencyPlanOverride changes properties of the built-in frequency plan of a
+// region. It is defined in a file; properties that are not set keep the value
+// of the built-in frequency plan.
+type FrequencyPlanOverride struct {
+	DutyCycle *bool          `yaml:"duty-cycle"`
+	DwellTime *time.Duration `yaml:"dwell-time"`
+	// RX2DataRate is the index of the RX2 data rate, which may be FSK
+	RX2DataRate *int `yaml:"rx2-data-rate"`
+	// RX1WindowTolerance is how much later than the start of RX1 a downlink
+	// can be sent and still be received by the devices
+	RX1WindowTolerance *time.Duration `yaml:"rx1-window-tolerance"`
+}
+
+// ReadFrequencyPlanOverride reads a frequency plan override from a YAML file
+func ReadFrequencyPlanOverride(filename string) (*FrequencyPlanOverride, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	override := new(FrequencyPlanOverride)
+	if err := yaml.Unmarshal(data, override); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+// Validate checks the override against the built-in frequency plan that it is
+// applied to and returns all problems that were found
+func (o *FrequencyPlanOverride) Validate(plan FrequencyPlan) (errs []error) {
+	if o.DwellTime != nil && *o.DwellTime < 0 {
+		errs = append(errs, errors.NewErrInvalidArgument("Dwell time", fmt.Sprintf("%s is negative", *o.DwellTime)))
+	}
+	if o.RX2DataRate != nil && (*o.RX2DataRate < 0 || *o.RX2DataRate >= len(plan.DataRates)) {
+		errs = append(errs, errors.NewErrInvalidArgument("RX2 data rate", fmt.Sprintf("%d is not in band", *o.RX2DataRate)))
+	}
+	if o.RX1WindowTolerance != nil && *o.RX1WindowTolerance < 0 {
+		errs = append(errs, errors.NewErrInvalidArgument("RX1 window tolerance", fmt.Sprintf("%s is negative", *o.RX1WindowTolerance)))
+	}
+	return
+}
+
+// Apply applies the override to the frequency plan
+func (o *FrequencyPlanOverride) Apply(plan *FrequencyPlan) error {
+	if errs := o.Validate(*plan); len(errs) != 0 {
+		return errs[0]
+	}
+	if o.DutyCycle != nil {
+		plan.DutyCycle = *o.DutyCycle
+	}
+	if o.DwellTime != nil {
+		plan.DwellTime = *o.DwellTime
+	}
+	if o.RX2DataRate != nil {
+		plan.RX2DataRate = *o.RX2DataRate
+	}
+	if o.RX1WindowTolerance != nil {
+		plan.RX1WindowTolerance = *o.RX1WindowTolerance
+	}
+	return nil
+}
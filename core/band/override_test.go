@@ -0,0 +1,70 @@
+package band
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestReadFrequencyPlanOverride(t *testing.T) {
+	a := New(t)
+
+	file, err := ioutil.TempFile("", "frequency-plan-override")
+	a.So(err, ShouldBeNil)
+	defer os.Remove(file.Name())
+	file.WriteString(`duty-cycle: false
+dwell-time: 400ms
+rx2-data-rate: 7
+rx1-window-tolerance: 10ms
+`)
+	file.Close()
+
+	override, err := ReadFrequencyPlanOverride(file.Name())
+	a.So(err, ShouldBeNil)
+
+	plan, _ := Get("EU_863_870")
+	a.So(plan.DutyCycle, ShouldBeTrue)
+	a.So(override.Apply(&plan), ShouldBeNil)
+	a.So(plan.DutyCycle, ShouldBeFalse)
+	a.So(plan.DwellTime, ShouldEqual, 400*time.Millisecond)
+	a.So(plan.RX2DataRate, ShouldEqual, 7) // FSK 50kbps
+	a.So(plan.RX1WindowTolerance, ShouldEqual, 10*time.Millisecond)
+
+	// Data rates that are not in the band are invalid
+	plan, _ = Get("EU_863_870")
+	invalid := 16
+	a.So((&FrequencyPlanOverride{RX2DataRate: &invalid}).Apply(&plan), ShouldNotBeNil)
+
+	// Properties that are not set keep their value
+	plan, _ = Get("EU_863_870")
+	a.So((&FrequencyPlanOverride{}).Apply(&plan), ShouldBeNil)
+	a.So(plan.DutyCycle, ShouldBeTrue)
+	a.So(plan.DwellTime, ShouldEqual, 0)
+	a.So(plan.RX2DataRate, ShouldEqual, 3)
+	a.So(plan.RX1WindowTolerance, ShouldEqual, 5*time.Millisecond)
+}
+
+func TestFrequencyPlanOverrideValidate(t *testing.T) {
+	a := New(t)
+
+	plan, _ := Get("EU_863_870")
+
+	dwellTime, rx2DataRate := 400*time.Millisecond, 7
+	a.So((&FrequencyPlanOverride{DwellTime: &dwellTime, RX2DataRate: &rx2DataRate}).Validate(plan), ShouldBeEmpty)
+
+	// All problems are returned
+	dwellTime, rx2DataRate, tolerance := -time.Second, 16, -time.Millisecond
+	errs := (&FrequencyPlanOverride{
+		DwellTime:          &dwellTime,
+		RX2DataRate:        &rx2DataRate,
+		RX1WindowTolerance: &tolerance,
+	}).Validate(plan)
+	a.So(errs, ShouldHaveLength, 3)
+	for _, err := range errs {
+		a.So(errors.GetErrType(err), ShouldEqual, errors.InvalidArgument)
+	}
+}
@@ -24,9 +24,12 @@ type Broker interface {
 	component.ManagementInterface
 
 	SetNetworkServer(addr, cert, token string)
+	SetKeepGatewayDuplicates(keep bool)
+	SetDownlinkOptionsSort(sort string)
 
 	HandleUplink(uplink *pb.UplinkMessage) error
 	HandleDownlink(downlink *pb.DownlinkMessage) error
+	HandleDownlinkResult(routerID string, result *pb.DownlinkResult) error
 	HandleActivation(activation *pb.DeviceActivationRequest) (*pb.DeviceActivationResponse, error)
 
 	ActivateRouter(id string) (<-chan *pb.DownlinkMessage, error)
@@ -50,6 +53,20 @@ func (b *broker) SetNetworkServer(addr, cert, token string) {
 	b.nsToken = token
 }
 
+// SetKeepGatewayDuplicates configures whether duplicate uplinks that are
+// reported by the same gateway are kept. By default, only the copy with the
+// best metadata is kept.
+func (b *broker) SetKeepGatewayDuplicates(keep bool) {
+	b.keepGatewayDuplicates = keep
+}
+
+// SetDownlinkOptionsSort configures the order (score, window or airtime) in
+// which routers should send the downlink options of uplink. By default, routers
+// use their default order.
+func (b *broker) SetDownlinkOptionsSort(sort string) {
+	b.downlinkOptionsSort = sort
+}
+
 type broker struct {
 	*component.Component
 	routers                map[string]chan *pb.DownlinkMessage
@@ -63,6 +80,8 @@ type broker struct {
 	ns                     networkserver.NetworkServerClient
 	uplinkDeduplicator     Deduplicator
 	activationDeduplicator Deduplicator
+	keepGatewayDuplicates  bool
+	downlinkOptionsSort    string
 	status                 *status
 }
 
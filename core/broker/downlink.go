@@ -59,3 +59,23 @@ func (b *broker) HandleDownlink(downlink *pb.DownlinkMessage) error {
 
 	return nil
 }
+
+// HandleDownlinkResult handles the result of scheduling a downlink, as it is
+// sent back by the router of the downlink
+func (b *broker) HandleDownlinkResult(routerID string, result *pb.DownlinkResult) error {
+	ctx := b.Ctx.WithFields(log.Fields{
+		"RouterID": routerID,
+		"AppID":    result.AppId,
+		"DevID":    result.DevId,
+	})
+	if !result.Accepted {
+		ctx.WithField("Reason", result.NackReason).Warn("Router did not schedule downlink")
+		return nil
+	}
+	ctx.WithFields(log.Fields{
+		"GatewayID": result.GatewayId,
+		"Window":    result.Window,
+		"Frequency": result.Frequency,
+	}).Debug("Router scheduled downlink")
+	return nil
+}
@@ -60,3 +60,27 @@ func TestDownlink(t *testing.T) {
 	a.So(err, ShouldBeNil)
 	a.So(len(dlch), ShouldEqual, 1)
 }
+
+func TestDownlinkResult(t *testing.T) {
+	a := New(t)
+
+	b := &broker{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestDownlinkResult"),
+		},
+	}
+
+	a.So(b.HandleDownlinkResult("routerID", &pb.DownlinkResult{
+		AppId:     "app",
+		DevId:     "dev",
+		Accepted:  true,
+		GatewayId: "gateway",
+		Window:    "RX1",
+	}), ShouldBeNil)
+
+	a.So(b.HandleDownlinkResult("routerID", &pb.DownlinkResult{
+		AppId:      "app",
+		DevId:      "dev",
+		NackReason: "DUTY_CYCLE",
+	}), ShouldBeNil)
+}
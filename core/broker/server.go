@@ -43,6 +43,10 @@ func (b *brokerRPC) associateRouter(md metadata.MD) (chan *pb.UplinkMessage, <-c
 
 	go func() {
 		for message := range up {
+			if message.DownlinkResult != nil {
+				go b.broker.HandleDownlinkResult(router.Id, message.DownlinkResult)
+				continue
+			}
 			if waitTime := b.routerUpRate.Wait(router.Id); waitTime != 0 {
 				b.broker.Ctx.WithField("RouterID", router.Id).WithField("Wait", waitTime).Warn("Router reached uplink rate limit")
 				time.Sleep(waitTime)
@@ -127,6 +131,7 @@ func (b *broker) RegisterRPC(s *grpc.Server) {
 	server := &brokerRPC{broker: b}
 	server.SetLogger(apex.Wrap(b.Ctx))
 	server.RouterAssociateChanFunc = server.associateRouter
+	server.DownlinkOptionsSort = b.downlinkOptionsSort
 	server.HandlerPublishChanFunc = server.getHandlerPublish
 	server.HandlerSubscribeChanFunc = server.getHandlerSubscribe
 
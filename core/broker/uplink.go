@@ -223,9 +223,42 @@ func (b *broker) deduplicateUplink(duplicate *pb.UplinkMessage) (uplinks []*pb.U
 	for _, duplicate := range list {
 		uplinks = append(uplinks, duplicate.(*pb.UplinkMessage))
 	}
+	if !b.keepGatewayDuplicates {
+		uplinks = deduplicateGateways(uplinks)
+	}
+	return
+}
+
+// deduplicateGateways collapses uplinks that were reported more than once by
+// the same gateway to the copy with the best metadata
+func deduplicateGateways(duplicates []*pb.UplinkMessage) (uplinks []*pb.UplinkMessage) {
+	indices := make(map[string]int)
+	for _, duplicate := range duplicates {
+		if duplicate.GatewayMetadata == nil || duplicate.GatewayMetadata.GatewayId == "" {
+			uplinks = append(uplinks, duplicate)
+			continue
+		}
+		gatewayID := duplicate.GatewayMetadata.GatewayId
+		if i, ok := indices[gatewayID]; ok {
+			if hasBetterMetadata(duplicate.GatewayMetadata, uplinks[i].GatewayMetadata) {
+				uplinks[i] = duplicate
+			}
+			continue
+		}
+		indices[gatewayID] = len(uplinks)
+		uplinks = append(uplinks, duplicate)
+	}
 	return
 }
 
+// hasBetterMetadata returns true if a has a better SNR (or RSSI) than b
+func hasBetterMetadata(a, b *gateway.RxMetadata) bool {
+	if a.Snr != b.Snr {
+		return a.Snr > b.Snr
+	}
+	return a.Rssi > b.Rssi
+}
+
 func selectBestDownlink(options []*pb.DownlinkOption) *pb.DownlinkOption {
 	sort.Sort(ByScore(options))
 	return options[0]
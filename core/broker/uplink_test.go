@@ -183,3 +183,39 @@ func TestDeduplicateUplink(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestDeduplicateUplinkSameGateway(t *testing.T) {
+	a := New(t)
+
+	payload := []byte{0x01, 0x02, 0x03}
+	protocolMetadata := &protocol.RxMetadata{}
+	uplink1 := &pb.UplinkMessage{Payload: payload, GatewayMetadata: &gateway.RxMetadata{GatewayId: "gtw-1", Snr: 1.2}, ProtocolMetadata: protocolMetadata}
+	uplink2 := &pb.UplinkMessage{Payload: payload, GatewayMetadata: &gateway.RxMetadata{GatewayId: "gtw-1", Snr: 3.4}, ProtocolMetadata: protocolMetadata}
+	uplink3 := &pb.UplinkMessage{Payload: payload, GatewayMetadata: &gateway.RxMetadata{GatewayId: "gtw-2", Snr: 5.6}, ProtocolMetadata: protocolMetadata}
+
+	deduplicate := func(keepGatewayDuplicates bool) (res []*pb.UplinkMessage) {
+		b := getTestBroker(t)
+		b.uplinkDeduplicator = NewDeduplicator(20 * time.Millisecond).(*deduplicator)
+		b.SetKeepGatewayDuplicates(keepGatewayDuplicates)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			res = b.deduplicateUplink(uplink1)
+			wg.Done()
+		}()
+
+		<-time.After(10 * time.Millisecond)
+
+		a.So(b.deduplicateUplink(uplink2), ShouldBeNil)
+		a.So(b.deduplicateUplink(uplink3), ShouldBeNil)
+
+		wg.Wait()
+		return
+	}
+
+	// The duplicate of gtw-1 with the best metadata is kept
+	a.So(deduplicate(false), ShouldResemble, []*pb.UplinkMessage{uplink2, uplink3})
+
+	a.So(deduplicate(true), ShouldResemble, []*pb.UplinkMessage{uplink1, uplink2, uplink3})
+}
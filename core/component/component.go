@@ -18,6 +18,7 @@ import (
 	pb_monitor "github.com/TheThingsNetwork/ttn/api/monitor"
 	"github.com/apex/log"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
@@ -34,6 +35,7 @@ type Component struct {
 	privateKey       *ecdsa.PrivateKey
 	tlsConfig        *tls.Config
 	TokenKeyProvider tokenkey.Provider
+	TracerProvider   trace.TracerProvider
 	status           int64
 	healthServer     *health.Server
 }
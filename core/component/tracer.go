@@ -0,0 +1,20 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package component
+
+import "go.opentelemetry.io/otel/trace"
+
+// Tracing returns true if the component has an OpenTelemetry TracerProvider
+func (c *Component) Tracing() bool {
+	return c != nil && c.TracerProvider != nil
+}
+
+// Tracer returns the OpenTelemetry tracer with the given name. If the
+// component has no TracerProvider, the tracer does not record any spans.
+func (c *Component) Tracer(name string) trace.Tracer {
+	if !c.Tracing() {
+		return trace.NewNoopTracerProvider().Tracer(name)
+	}
+	return c.TracerProvider.Tracer(name)
+}
@@ -12,7 +12,6 @@ import (
 	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
-	"github.com/TheThingsNetwork/ttn/core/band"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/apex/log"
 )
@@ -77,15 +76,7 @@ func (r *router) HandleActivation(gatewayID string, activation *pb.DeviceActivat
 	}
 
 	// Prepare LoRaWAN activation
-	status, err := gateway.Status.Get()
-	if err != nil {
-		return nil, err
-	}
-	region := status.Region
-	if region == "" {
-		region = band.Guess(uplink.GatewayMetadata.Frequency)
-	}
-	band, err := band.Get(region)
+	band, err := r.getFrequencyPlan(getRegion(gateway, uplink.GatewayMetadata.Frequency))
 	if err != nil {
 		return nil, err
 	}
@@ -111,10 +102,14 @@ func (r *router) HandleActivation(gatewayID string, activation *pb.DeviceActivat
 			continue
 		}
 
+		// Send the downlink options in the order that the broker requested
+		request := *request
+		request.DownlinkOptions = sortedDownlinkOptions(downlinkOptions, broker.sortMode())
+
 		// Do async request
 		wg.Add(1)
 		go func() {
-			res, err := broker.client.Activate(r.Component.GetContext(""), request)
+			res, err := broker.client.Activate(r.Component.GetContext(""), &request)
 			if err == nil && res != nil {
 				responses <- res
 			}
@@ -139,7 +134,7 @@ func (r *router) HandleActivation(gatewayID string, activation *pb.DeviceActivat
 				Message:        res.Message,
 				DownlinkOption: res.DownlinkOption,
 			}
-			err := r.HandleDownlink(downlink)
+			_, err := r.HandleDownlink(downlink)
 			if err != nil {
 				ctx.Warn("Could not send downlink for Activation")
 				gotFirst = false // try again
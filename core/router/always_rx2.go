@@ -0,0 +1,58 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"sync"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+)
+
+// rx2OptionTimeout is the time after which RX2 options can no longer be used
+// together with their RX1 option
+const rx2OptionTimeout = 10 * time.Second
+
+type rx2Option struct {
+	option    *pb_broker.DownlinkOption
+	createdAt time.Time
+}
+
+// rx2Options keeps track of the RX2 option that belongs to each RX1 option, so
+// that a downlink in RX1 can also be sent in RX2
+type rx2Options struct {
+	sync.Mutex
+	options map[string]rx2Option
+	sweptAt time.Time
+}
+
+func (o *rx2Options) add(rx1Identifier string, rx2 *pb_broker.DownlinkOption) {
+	o.Lock()
+	defer o.Unlock()
+	now := time.Now()
+	if o.options == nil {
+		o.options = make(map[string]rx2Option)
+	}
+	if now.Sub(o.sweptAt) > rx2OptionTimeout {
+		for identifier, option := range o.options {
+			if now.Sub(option.createdAt) > rx2OptionTimeout {
+				delete(o.options, identifier)
+			}
+		}
+		o.sweptAt = now
+	}
+	o.options[rx1Identifier] = rx2Option{option: rx2, createdAt: now}
+}
+
+// get returns and removes the RX2 option that belongs to the RX1 option
+func (o *rx2Options) get(rx1Identifier string) (*pb_broker.DownlinkOption, bool) {
+	o.Lock()
+	defer o.Unlock()
+	option, ok := o.options[rx1Identifier]
+	if !ok || time.Since(option.createdAt) > rx2OptionTimeout {
+		return nil, false
+	}
+	delete(o.options, rx1Identifier)
+	return option.option, true
+}
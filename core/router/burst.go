@@ -0,0 +1,113 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"context"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrBurstDutyCycle is returned when the duty cycle of the gateway does not
+// allow the frames of a downlink burst
+var ErrBurstDutyCycle = errors.New("Downlink burst exceeds duty cycle")
+
+// HandleDownlinkBurst schedules the downlinks as a burst of frames with a fixed
+// interval, in the downlink option of the first downlink. The burst is only
+// scheduled if each frame passes the same checks as a regular downlink, the
+// entire burst fits in the airtime quotas, and none of the frames conflicts
+// with other transmissions.
+func (r *router) HandleDownlinkBurst(interval time.Duration, downlinks ...*pb_broker.DownlinkMessage) (err error) {
+	_, span := r.Tracer(tracerName).Start(context.Background(), "router.HandleDownlinkBurst")
+	span.SetAttributes(
+		attribute.Int("frames", len(downlinks)),
+		attribute.Int64("interval_us", int64(interval/time.Microsecond)),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
+	if len(downlinks) == 0 {
+		return errors.NewErrInvalidArgument("Downlink burst", "no downlinks")
+	}
+	option := downlinks[0].DownlinkOption
+	if option == nil || option.GatewayConfig == nil {
+		return errors.NewErrInvalidArgument("Downlink burst", "no downlink option")
+	}
+	span.SetAttributes(attribute.String("gateway_id", option.GatewayId))
+	lorawan := option.GetProtocolConfig().GetLorawan()
+	if lorawan == nil {
+		return errors.NewErrInvalidArgument("Downlink burst", "no LoRaWAN configuration")
+	}
+
+	r.status.downlink.Mark(1)
+
+	if err := r.checkSafeMode(); err != nil {
+		return err
+	}
+
+	for _, downlink := range downlinks {
+		if computeTimeOnAir(lorawan, uint(len(downlink.Payload))) > interval {
+			return errors.NewErrInvalidArgument("Downlink burst", "frames are longer than the interval")
+		}
+	}
+
+	gateway := r.getGateway(option.GatewayId)
+
+	// Each frame is checked like a regular downlink, which takes the duty
+	// cycle of the group of the gateway and the dwell time into account
+	frames := make([]*pb_broker.DownlinkMessage, 0, len(downlinks))
+	for _, downlink := range downlinks {
+		frame := *downlink
+		frame.DownlinkOption = option
+		if reason, err := r.checkDownlink(gateway, &frame); err != nil {
+			if reason == NackDutyCycle {
+				return ErrBurstDutyCycle
+			}
+			return err
+		}
+		frames = append(frames, &frame)
+	}
+
+	// Charge the airtime of all frames, and refund it if the burst is not
+	// scheduled
+	var charged []*pb_broker.DownlinkMessage
+	refund := func() {
+		for _, frame := range charged {
+			r.refundDownlink(gateway, frame)
+		}
+	}
+	for _, frame := range frames {
+		if _, err := r.chargeDownlink(gateway, frame); err != nil {
+			refund()
+			return err
+		}
+		charged = append(charged, frame)
+	}
+
+	downlinkMessages := make([]*pb.DownlinkMessage, 0, len(frames))
+	for i, frame := range frames {
+		gatewayConfig := *option.GatewayConfig
+		gatewayConfig.Timestamp += uint32(time.Duration(i) * interval / time.Microsecond)
+		downlinkMessages = append(downlinkMessages, &pb.DownlinkMessage{
+			Payload:               frame.Payload,
+			ProtocolConfiguration: option.ProtocolConfig,
+			GatewayConfiguration:  &gatewayConfig,
+			TraceId:               frame.TraceId,
+		})
+	}
+
+	if err := gateway.HandleDownlinkBurst(downlinkMessages); err != nil {
+		refund()
+		return err
+	}
+	return nil
+}
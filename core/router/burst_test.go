@@ -0,0 +1,172 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandleDownlinkBurst(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkBurst"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+		dutyCycleGroupConfig: map[string]string{
+			"eui-0102030405060708": "site",
+			"eui-0102030405060709": "site",
+		},
+	}
+	r.InitStatus()
+
+	burst := func(gtw *gateway.Gateway) []*pb_broker.DownlinkMessage {
+		option := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)[1]
+		downlinks := make([]*pb_broker.DownlinkMessage, 3)
+		for i := range downlinks {
+			downlinks[i] = &pb_broker.DownlinkMessage{
+				Payload:        make([]byte, 20),
+				DownlinkOption: option,
+			}
+		}
+		return downlinks
+	}
+
+	// The number of transmissions that is scheduled at each frame of the burst
+	scheduled := func(gtw *gateway.Gateway) (frames int) {
+		for i := 0; i < 3; i++ {
+			if gtw.Schedule.Conflicts(1000100+uint32(i)*100000, 1) >= 100 {
+				frames++
+			}
+		}
+		return
+	}
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	// Frames must fit in the interval
+	err := r.HandleDownlinkBurst(10*time.Millisecond, burst(gtw)...)
+	a.So(err, ShouldNotBeNil)
+	a.So(scheduled(gtw), ShouldEqual, 0)
+
+	// Another gateway shares its duty cycle with the first gateway
+	shared := r.getGateway("eui-0102030405060709")
+	shared.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	// The duty cycle of the channel is exceeded after the options were built
+	downlinks, sharedDownlinks := burst(gtw), burst(shared)
+	for i := 0; i < 5; i++ {
+		gtw.Utilization.AddTx(newReferenceDownlink())
+	}
+	gtw.Utilization.Tick()
+	err = r.HandleDownlinkBurst(100*time.Millisecond, downlinks...)
+	a.So(err, ShouldEqual, ErrBurstDutyCycle)
+	a.So(scheduled(gtw), ShouldEqual, 0)
+
+	// The burst does not fit in the duty cycle of the group either
+	err = r.HandleDownlinkBurst(100*time.Millisecond, sharedDownlinks...)
+	a.So(err, ShouldEqual, ErrBurstDutyCycle)
+	a.So(scheduled(shared), ShouldEqual, 0)
+
+	// The burst fits in the duty cycle of a gateway outside the group
+	other := r.getGateway("eui-0807060504030201")
+	other.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+	err = r.HandleDownlinkBurst(100*time.Millisecond, burst(other)...)
+	a.So(err, ShouldBeNil)
+	a.So(scheduled(other), ShouldEqual, 3)
+}
+
+func TestHandleDownlinkBurstQuota(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkBurstQuota"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	up := newReferenceUplink()
+	option := r.buildDownlinkOptions(up, false, gtw)[1]
+	burst := func(frames int) []*pb_broker.DownlinkMessage {
+		downlinks := make([]*pb_broker.DownlinkMessage, frames)
+		for i := range downlinks {
+			downlinks[i] = &pb_broker.DownlinkMessage{
+				Payload:        up.Payload,
+				DownlinkOption: option,
+			}
+		}
+		return downlinks
+	}
+
+	// The quota of the network allows two frames
+	r.airtimeQuota = 2 * computeTimeOnAir(option.ProtocolConfig.GetLorawan(), uint(len(up.Payload)))
+
+	err := r.HandleDownlinkBurst(100*time.Millisecond, burst(3)...)
+	a.So(err, ShouldEqual, ErrQuotaExceeded)
+	a.So(gtw.Schedule.PendingDownlinks(), ShouldBeEmpty)
+
+	// The frames of the burst that was not scheduled were refunded
+	err = r.HandleDownlinkBurst(100*time.Millisecond, burst(2)...)
+	a.So(err, ShouldBeNil)
+	a.So(gtw.Schedule.PendingDownlinks(), ShouldHaveLength, 2)
+}
+
+func TestHandleDownlinkBurstSpan(t *testing.T) {
+	a := New(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	r := &router{
+		Component: &component.Component{
+			Ctx:            GetLogger(t, "TestHandleDownlinkBurstSpan"),
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	option := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)[1]
+	downlinks := []*pb_broker.DownlinkMessage{
+		{Payload: make([]byte, 20), DownlinkOption: option},
+		{Payload: make([]byte, 20), DownlinkOption: option},
+	}
+	err := r.HandleDownlinkBurst(100*time.Millisecond, downlinks...)
+	a.So(err, ShouldBeNil)
+
+	// The span of the gateway ends before the span of the router
+	spans := exporter.GetSpans()
+	a.So(spans, ShouldHaveLength, 2)
+	a.So(spans[0].Name, ShouldEqual, "gateway.HandleDownlinkBurst")
+	a.So(spans[1].Name, ShouldEqual, "router.HandleDownlinkBurst")
+	attributes := spanAttributes(spans[1])
+	a.So(attributes["gateway_id"].AsString(), ShouldEqual, gtw.ID)
+	a.So(attributes["frames"].AsInt64(), ShouldEqual, 2)
+	a.So(spans[1].Events, ShouldBeEmpty)
+
+	// Errors are recorded in the span
+	err = r.HandleDownlinkBurst(10*time.Millisecond, downlinks...)
+	a.So(err, ShouldNotBeNil)
+	spans = exporter.GetSpans()
+	a.So(spans, ShouldHaveLength, 3)
+	a.So(spans[2].Events, ShouldHaveLength, 1)
+}
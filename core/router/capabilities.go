@@ -0,0 +1,98 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"sort"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	lora "github.com/brocaar/lorawan/band"
+)
+
+// Features of the router that clients can discover with Capabilities
+const (
+	FeatureClassA           = "class-a"
+	FeatureClassB           = "class-b"
+	FeatureDownlinkAttempts = "downlink-attempts"
+	FeatureDownlinkRescore  = "downlink-rescore"
+	FeatureAirtimeQuota     = "airtime-quota"
+)
+
+// Capabilities returns the frequency plans, modulations and features that are
+// supported by this router
+func (r *router) Capabilities() *pb.Capabilities {
+	capabilities := new(pb.Capabilities)
+	modulations := make(map[lora.Modulation]bool)
+	for _, region := range pb_lorawan.Region_name {
+		plan, err := r.getFrequencyPlan(region)
+		if err != nil {
+			continue
+		}
+		capabilities.FrequencyPlans = append(capabilities.FrequencyPlans, region)
+		for _, modulation := range downlinkModulations(plan) {
+			modulations[modulation] = true
+		}
+	}
+	sort.Strings(capabilities.FrequencyPlans)
+	if modulations[lora.LoRaModulation] {
+		capabilities.Modulations = append(capabilities.Modulations, pb_lorawan.Modulation_LORA.String())
+	}
+	if modulations[lora.FSKModulation] {
+		capabilities.Modulations = append(capabilities.Modulations, pb_lorawan.Modulation_FSK.String())
+	}
+	capabilities.Features = []string{FeatureClassA}
+	if r.classBAvailable() {
+		capabilities.Features = append(capabilities.Features, FeatureClassB)
+	}
+	if r.downlinkAttempts {
+		capabilities.Features = append(capabilities.Features, FeatureDownlinkAttempts)
+	}
+	if r.rescore {
+		capabilities.Features = append(capabilities.Features, FeatureDownlinkRescore)
+	}
+	if r.airtimeQuota > 0 {
+		capabilities.Features = append(capabilities.Features, FeatureAirtimeQuota)
+	}
+	return capabilities
+}
+
+// classBAvailable returns true if Class B downlink can be scheduled through at
+// least one of the gateways of the router
+func (r *router) classBAvailable() bool {
+	r.gatewaysLock.RLock()
+	defer r.gatewaysLock.RUnlock()
+	for _, gateway := range r.gateways {
+		if r.gatewayClassBAvailable(gateway) {
+			return true
+		}
+	}
+	return false
+}
+
+// downlinkModulations returns the modulations of the downlink that the router
+// can send in the frequency plan: the RX1 data rates of the uplink channels
+// and the RX2 data rate
+func downlinkModulations(plan band.FrequencyPlan) (modulations []lora.Modulation) {
+	seen := make(map[lora.Modulation]bool)
+	add := func(dataRate int) {
+		if dataRate < 0 || dataRate >= len(plan.DataRates) {
+			return
+		}
+		if modulation := plan.DataRates[dataRate].Modulation; !seen[modulation] {
+			seen[modulation] = true
+			modulations = append(modulations, modulation)
+		}
+	}
+	for _, channel := range plan.UplinkChannels {
+		for _, dataRate := range channel.DataRates {
+			if rx1, err := plan.GetRX1DataRate(dataRate); err == nil {
+				add(rx1)
+			}
+		}
+	}
+	add(plan.RX2DataRate)
+	return
+}
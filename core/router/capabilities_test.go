@@ -0,0 +1,73 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	lora "github.com/brocaar/lorawan/band"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestCapabilities(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+	capabilities := r.Capabilities()
+	for _, region := range []string{"AS_923", "AU_915_928", "CN_470_510", "EU_863_870", "KR_920_923", "US_902_928"} {
+		a.So(capabilities.FrequencyPlans, ShouldContain, region)
+	}
+	a.So(capabilities.FrequencyPlans, ShouldNotContain, "EU_433") // Not supported
+
+	// EU_863_870 has FSK downlink in RX1 of its FSK uplink channel
+	a.So(capabilities.Modulations, ShouldResemble, []string{"LORA", "FSK"})
+
+	// Class B needs a gateway that is synchronized with GPS
+	a.So(capabilities.Features, ShouldContain, FeatureClassA)
+	a.So(capabilities.Features, ShouldNotContain, FeatureClassB)
+	a.So(capabilities.Features, ShouldNotContain, FeatureDownlinkAttempts)
+	a.So(capabilities.Features, ShouldNotContain, FeatureAirtimeQuota)
+
+	r = &router{airtimeQuota: time.Second, downlinkAttempts: true}
+	a.So(r.Capabilities().Features, ShouldContain, FeatureAirtimeQuota)
+	a.So(r.Capabilities().Features, ShouldContain, FeatureDownlinkAttempts)
+}
+
+func TestCapabilitiesClassB(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		gateways:              map[string]*gateway.Gateway{},
+		gpsLostSuspendsClassB: true,
+	}
+
+	gtw := newReferenceGateway(t, "EU_863_870")
+	r.gateways[gtw.ID] = gtw
+	a.So(r.Capabilities().Features, ShouldNotContain, FeatureClassB)
+
+	gtw.HandleStatus(&pb_gateway.Status{Region: "EU_863_870", Gps: &pb_gateway.GPSMetadata{Time: time.Now().UnixNano()}})
+	a.So(r.Capabilities().Features, ShouldContain, FeatureClassB)
+
+	// Class B is suspended while the gateway has no GPS lock
+	gtw.HandleStatus(&pb_gateway.Status{Region: "EU_863_870"})
+	a.So(r.Capabilities().Features, ShouldNotContain, FeatureClassB)
+
+	r.gpsLostSuspendsClassB = false
+	a.So(r.Capabilities().Features, ShouldContain, FeatureClassB)
+}
+
+func TestDownlinkModulations(t *testing.T) {
+	a := New(t)
+
+	// The FSK uplink channel of EU_863_870 gets FSK downlink in RX1
+	plan, _ := band.Get("EU_863_870")
+	a.So(downlinkModulations(plan), ShouldResemble, []lora.Modulation{lora.LoRaModulation, lora.FSKModulation})
+
+	plan, _ = band.Get("US_902_928")
+	a.So(downlinkModulations(plan), ShouldResemble, []lora.Modulation{lora.LoRaModulation})
+}
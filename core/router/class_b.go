@@ -0,0 +1,99 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+const (
+	// beaconReserved is the time after the start of the beacon that is reserved for the beacon
+	beaconReserved = 2120 * time.Millisecond
+	// pingSlotLength is the length of a ping slot
+	pingSlotLength = 30 * time.Millisecond
+	// pingSlots is the number of ping slots in a beacon period
+	pingSlots = 4096
+)
+
+// pingOffset computes the pseudo-random offset (in slots) of the ping slots of
+// a device in the beacon period that starts at beaconTime (in GPS seconds).
+// This makes sure that devices with the same ping period don't always use the
+// same slots.
+func pingOffset(beaconTime uint32, devAddr types.DevAddr, pingPeriod uint16) uint16 {
+	block, _ := aes.NewCipher(make([]byte, 16)) // The key is all zeroes
+	in, out := make([]byte, 16), make([]byte, 16)
+	binary.LittleEndian.PutUint32(in[0:4], beaconTime)
+	binary.LittleEndian.PutUint32(in[4:8], binary.BigEndian.Uint32(devAddr[:]))
+	block.Encrypt(out, in)
+	return (uint16(out[0]) + uint16(out[1])*256) % pingPeriod
+}
+
+// buildPingSlotOption builds the downlink option for the first ping slot of a
+// Class B device in the beacon period that starts at beaconTime (in GPS
+// seconds). The beaconTimestamp is the timestamp of that beacon in the clock of
+// the gateway and pingNb is the number of ping slots per beacon period. If the
+// gateway lost its GPS lock, Class B is suspended and the device has to be
+// reached in Class A.
+func (r *router) buildPingSlotOption(gateway *gateway.Gateway, devAddr types.DevAddr, beaconTime, beaconTimestamp uint32, pingNb uint32) (*pb_broker.DownlinkOption, error) {
+	if pingNb == 0 || pingNb > 128 || pingNb&(pingNb-1) != 0 {
+		return nil, errors.NewErrInvalidArgument("PingNb", fmt.Sprintf("%d is not a power of two between 1 and 128", pingNb))
+	}
+
+	if r.gpsLostSuspendsClassB && gateway.GPSLost() {
+		return nil, errors.NewErrInternal(fmt.Sprintf("Gateway %s lost GPS lock, Class B suspended", gateway.ID))
+	}
+
+	band, err := r.getFrequencyPlan(getRegion(gateway, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	offset := pingOffset(beaconTime, devAddr, uint16(pingSlots/pingNb))
+
+	option := r.buildDownlinkOption(gateway.ID, band)
+	option.GatewayConfig.Timestamp = beaconTimestamp + uint32((beaconReserved+time.Duration(offset)*pingSlotLength)/time.Microsecond)
+	if option.GatewayConfig.Power, err = gateway.TXPower(option.GatewayConfig.Power); err != nil {
+		return nil, err
+	}
+
+	lorawan := option.ProtocolConfig.GetLorawan()
+	lorawan.CodingRate = "4/5"
+	option.Identifier, _ = gateway.Schedule.GetOption(option.GatewayConfig.Timestamp, uint32(computeTimeOnAir(lorawan, 51+13)/1000))
+
+	return option, nil
+}
+
+// gatewayClassBAvailable returns true if Class B downlink can be scheduled
+// through the gateway: it was synchronized with GPS, and Class B is not
+// suspended because it lost its GPS lock
+func (r *router) gatewayClassBAvailable(gateway *gateway.Gateway) bool {
+	if _, _, ok := gateway.NextBeacon(time.Now()); !ok {
+		return false
+	}
+	return !r.gpsLostSuspendsClassB || !gateway.GPSLost()
+}
+
+// pingSlotOption builds the downlink option for the next ping slot of the
+// Class B device that the downlink is for, through the gateway of the downlink
+// option of the downlink
+func (r *router) pingSlotOption(downlink *pb_broker.DownlinkMessage) (*pb_broker.DownlinkOption, error) {
+	devAddr, ok := devAddrFromPayload(downlink.Payload)
+	if !ok {
+		return nil, errors.NewErrInvalidArgument("Downlink", "Class B downlink without DevAddr")
+	}
+	gateway := r.getGateway(downlink.DownlinkOption.GatewayId)
+	beaconTime, beaconTimestamp, ok := gateway.NextBeacon(time.Now())
+	if !ok {
+		return nil, errors.NewErrInternal(fmt.Sprintf("Gateway %s is not synchronized with GPS, Class B not available", gateway.ID))
+	}
+	return r.buildPingSlotOption(gateway, devAddr, beaconTime, beaconTimestamp, downlink.PingSlots)
+}
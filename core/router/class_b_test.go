@@ -0,0 +1,142 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPingOffset(t *testing.T) {
+	a := New(t)
+
+	devAddrA := types.DevAddr([4]byte{1, 2, 3, 4})
+	devAddrB := types.DevAddr([4]byte{4, 3, 2, 1})
+
+	// Devices get different ping slots in the same beacon period
+	a.So(pingOffset(1000, devAddrA, 32), ShouldNotEqual, pingOffset(1000, devAddrB, 32))
+
+	// The offset is deterministic
+	a.So(pingOffset(1000, devAddrA, 32), ShouldEqual, pingOffset(1000, devAddrA, 32))
+
+	// The offset is always within the ping period
+	for beaconTime := uint32(0); beaconTime < 128*100; beaconTime += 128 {
+		a.So(pingOffset(beaconTime, devAddrA, 32), ShouldBeLessThan, 32)
+	}
+}
+
+func TestBuildPingSlotOption(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+	gtw := newReferenceGateway(t, "EU_863_870")
+	gtw.Schedule.Sync(0)
+
+	devAddrA := types.DevAddr([4]byte{1, 2, 3, 4})
+	devAddrB := types.DevAddr([4]byte{4, 3, 2, 1})
+
+	_, err := r.buildPingSlotOption(gtw, devAddrA, 1000, 1000000, 3)
+	a.So(err, ShouldNotBeNil)
+
+	optionA, err := r.buildPingSlotOption(gtw, devAddrA, 1000, 1000000, 128)
+	a.So(err, ShouldBeNil)
+	optionB, err := r.buildPingSlotOption(gtw, devAddrB, 1000, 1000000, 128)
+	a.So(err, ShouldBeNil)
+
+	a.So(optionA.GatewayConfig.Frequency, ShouldEqual, 869525000)
+	a.So(optionA.GatewayConfig.Timestamp, ShouldEqual, 1000000+2120000+uint32(pingOffset(1000, devAddrA, 32))*30000)
+	a.So(optionB.GatewayConfig.Timestamp, ShouldEqual, 1000000+2120000+uint32(pingOffset(1000, devAddrB, 32))*30000)
+	a.So(optionA.GatewayConfig.Timestamp, ShouldNotEqual, optionB.GatewayConfig.Timestamp)
+}
+
+func TestBuildPingSlotOptionGPSLost(t *testing.T) {
+	a := New(t)
+
+	r := &router{gpsLostSuspendsClassB: true}
+	gtw := newReferenceGateway(t, "EU_863_870")
+	gtw.Schedule.Sync(0)
+	devAddr := types.DevAddr([4]byte{1, 2, 3, 4})
+
+	locked := &pb_gateway.Status{Region: "EU_863_870", Gps: &pb_gateway.GPSMetadata{Time: time.Now().UnixNano()}}
+	lost := &pb_gateway.Status{Region: "EU_863_870"}
+
+	gtw.HandleStatus(locked)
+	_, err := r.buildPingSlotOption(gtw, devAddr, 1000, 1000000, 128)
+	a.So(err, ShouldBeNil)
+
+	// Class B is suspended while the gateway has no GPS lock
+	gtw.HandleStatus(lost)
+	_, err = r.buildPingSlotOption(gtw, devAddr, 1128, 129000000, 128)
+	a.So(err, ShouldNotBeNil)
+
+	// Class B resumes when the gateway regains its GPS lock
+	gtw.HandleStatus(locked)
+	_, err = r.buildPingSlotOption(gtw, devAddr, 1256, 257000000, 128)
+	a.So(err, ShouldBeNil)
+}
+
+func TestHandleDownlinkClassB(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkClassB"),
+		},
+		gateways:              map[string]*gateway.Gateway{},
+		gpsLostSuspendsClassB: true,
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	locked := &pb_gateway.Status{Region: "EU_863_870", Timestamp: 1000, Gps: &pb_gateway.GPSMetadata{Time: time.Now().UnixNano()}}
+	lost := &pb_gateway.Status{Region: "EU_863_870"}
+
+	downlink := func(timestamp uint32) *pb_broker.DownlinkMessage {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Timestamp = timestamp
+		return &pb_broker.DownlinkMessage{
+			Payload:        up.Payload,
+			DownlinkOption: r.buildDownlinkOptions(up, false, gtw)[1],
+			PingSlots:      128,
+		}
+	}
+	pingSlot := func(devAddr types.DevAddr) uint32 {
+		beaconTime, beaconTimestamp, _ := gtw.NextBeacon(time.Now())
+		return beaconTimestamp + 2120000 + uint32(pingOffset(beaconTime, devAddr, 32))*30000
+	}
+
+	// Class B downlink is sent in the next ping slot of the device
+	gtw.HandleStatus(locked)
+	classB := downlink(0)
+	devAddr, _ := devAddrFromPayload(classB.Payload)
+	res, err := r.HandleDownlink(classB)
+	a.So(err, ShouldBeNil)
+	a.So(res.Frequency, ShouldEqual, 869525000)
+	a.So(res.Timestamp, ShouldEqual, pingSlot(devAddr))
+
+	// Class B downlink falls back to Class A while the gateway has no GPS lock
+	gtw.HandleStatus(lost)
+	classA := downlink(10000000)
+	res, err = r.HandleDownlink(classA)
+	a.So(err, ShouldBeNil)
+	a.So(res.Timestamp, ShouldEqual, classA.DownlinkOption.GatewayConfig.Timestamp)
+
+	// Class B resumes when the gateway regains its GPS lock; other devices use
+	// their own ping slots
+	gtw.HandleStatus(locked)
+	classB = downlink(20000000)
+	classB.Payload[1] = 0x05 // Another device with another ping slot
+	devAddr, _ = devAddrFromPayload(classB.Payload)
+	res, err = r.HandleDownlink(classB)
+	a.So(err, ShouldBeNil)
+	a.So(res.Timestamp, ShouldEqual, pingSlot(devAddr))
+}
@@ -0,0 +1,28 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ClearSchedule cancels all downlink options and transmissions of the gateway
+// that were not sent yet. It returns the number of cancelled ones.
+func (r *router) ClearSchedule(gatewayID string) (cleared int, err error) {
+	r.gatewaysLock.RLock()
+	gtw, ok := r.gateways[gatewayID]
+	r.gatewaysLock.RUnlock()
+	if !ok {
+		return 0, errors.NewErrNotFound(fmt.Sprintf("Gateway %s", gatewayID))
+	}
+	for _, id := range gtw.Schedule.Pending() {
+		if gtw.Schedule.Cancel(id) {
+			cleared++
+		}
+	}
+	gtw.Ctx.WithField("Cleared", cleared).Info("Cleared schedule")
+	return cleared, nil
+}
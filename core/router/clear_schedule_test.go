@@ -0,0 +1,51 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestClearSchedule(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestClearSchedule"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+
+	_, err := r.ClearSchedule("eui-0102030405060708")
+	a.So(err, ShouldNotBeNil)
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Schedule.Sync(0)
+
+	// Book some options and schedule one of them
+	var ids []string
+	for _, timestamp := range []uint32{1000000, 2000000, 3000000} {
+		id, _ := gtw.Schedule.GetOption(timestamp, 10000)
+		ids = append(ids, id)
+	}
+	a.So(gtw.Schedule.Schedule(ids[1], &pb.DownlinkMessage{}), ShouldBeNil)
+	a.So(gtw.Schedule.Pending(), ShouldHaveLength, 3)
+	a.So(gtw.Schedule.Conflicts(2000000, 1), ShouldBeGreaterThanOrEqualTo, 100)
+
+	cleared, err := r.ClearSchedule("eui-0102030405060708")
+	a.So(err, ShouldBeNil)
+	a.So(cleared, ShouldEqual, 3)
+	a.So(gtw.Schedule.Pending(), ShouldBeEmpty)
+	a.So(gtw.Schedule.Conflicts(2000000, 1), ShouldEqual, 0)
+
+	cleared, err = r.ClearSchedule("eui-0102030405060708")
+	a.So(err, ShouldBeNil)
+	a.So(cleared, ShouldEqual, 0)
+}
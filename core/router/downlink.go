@@ -4,6 +4,8 @@
 package router
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"strings"
@@ -16,10 +18,10 @@ import (
 	pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/core/band"
 	"github.com/TheThingsNetwork/ttn/core/router/gateway"
-	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
-	"github.com/TheThingsNetwork/ttn/utils/toa"
 	"github.com/apex/log"
+	lora "github.com/brocaar/lorawan/band"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func (r *router) SubscribeDownlink(gatewayID string, subscriptionID string) (<-chan *pb.DownlinkMessage, error) {
@@ -33,7 +35,11 @@ func (r *router) SubscribeDownlink(gatewayID string, subscriptionID string) (<-c
 		go func() {
 			ctx.Debug("Activate downlink")
 			for message := range fromSchedule {
-				gateway.Utilization.AddTx(message)
+				gateway.HandleSent(message)
+				ctx := ctx.WithField("TraceID", message.TraceId)
+				if r.traceFrames {
+					ctx = ctx.WithField("Frame", hex.EncodeToString(message.Payload))
+				}
 				ctx.Debug("Send downlink")
 				toGateway <- message
 			}
@@ -50,58 +56,356 @@ func (r *router) UnsubscribeDownlink(gatewayID string, subscriptionID string) er
 	return nil
 }
 
-func (r *router) HandleDownlink(downlink *pb_broker.DownlinkMessage) error {
-	r.status.downlink.Mark(1)
+func (r *router) Subscriptions() map[string]int {
+	r.gatewaysLock.RLock()
+	defer r.gatewaysLock.RUnlock()
+	subscriptions := make(map[string]int)
+	for id, gtw := range r.gateways {
+		if subscribers := gtw.Schedule.Subscribers(); subscribers > 0 {
+			subscriptions[id] = subscribers
+		}
+	}
+	return subscriptions
+}
+
+// gatewayDownlink converts a downlink message from the broker to the
+// identifier and downlink message for the gateway
+func (r *router) gatewayDownlink(downlink *pb_broker.DownlinkMessage) (identifier string, downlinkMessage *pb.DownlinkMessage) {
 	option := downlink.DownlinkOption
 
-	downlinkMessage := &pb.DownlinkMessage{
+	downlinkMessage = &pb.DownlinkMessage{
 		Payload:               downlink.Payload,
 		ProtocolConfiguration: option.ProtocolConfig,
 		GatewayConfiguration:  option.GatewayConfig,
+		TraceId:               downlink.TraceId,
 	}
 
-	identifier := option.Identifier
+	identifier = option.Identifier
 	if r.Component != nil && r.Component.Identity != nil {
 		identifier = strings.TrimPrefix(option.Identifier, fmt.Sprintf("%s:", r.Component.Identity.Id))
 	}
+	downlinkMessage.Identifier = identifier
+
+	return
+}
+
+func (r *router) HandleDownlink(downlink *pb_broker.DownlinkMessage) (res *DownlinkResult, err error) {
+	_, span := r.Tracer(tracerName).Start(context.Background(), "router.HandleDownlink")
+	defer func() {
+		if res != nil {
+			span.SetAttributes(attribute.Bool("accepted", res.Accepted))
+			if !res.Accepted {
+				span.SetAttributes(attribute.String("nack_reason", string(res.NackReason)))
+			}
+		}
+		span.End()
+	}()
+
+	r.status.downlink.Mark(1)
+	if err := r.checkSafeMode(); err != nil {
+		return nackDownlink(NackDownlinksDisabled), err
+	}
+	option := downlink.DownlinkOption
+	if option == nil || option.GatewayConfig == nil {
+		return nackDownlink(NackInvalid), errors.NewErrInvalidArgument("Downlink", "no downlink option")
+	}
+
+	// Rescoring keeps the window, so the option as it was built is traced
+	built, _ := r.optionTraces.get(option.Identifier)
+	window := built.window
+	if window != "" {
+		span.SetAttributes(
+			attribute.String("window", window),
+			attribute.Int("drops", len(built.drops)),
+			attribute.StringSlice("drop_reasons", built.drops),
+		)
+	}
+
+	// Use a better gateway if one became available after the uplink
+	if r.rescore {
+		if better := r.rescoreDownlink(downlink); better != nil {
+			r.Ctx.WithFields(log.Fields{
+				"GatewayID":       option.GatewayId,
+				"BetterGatewayID": better.GatewayId,
+			}).Debug("Use better gateway for downlink")
+			rescored := *downlink
+			rescored.DownlinkOption = better
+			downlink, option = &rescored, better
+		}
+	}
+
+	// Send Class B downlink in the next ping slot of the device. If that is not
+	// possible, the downlink falls back to Class A and uses its downlink option.
+	if downlink.PingSlots > 0 {
+		if pingSlot, err := r.pingSlotOption(downlink); err == nil {
+			classB := *downlink
+			classB.DownlinkOption = pingSlot
+			downlink, option = &classB, pingSlot
+			window = WindowPingSlot
+		} else {
+			r.Ctx.WithError(err).WithField("GatewayID", option.GatewayId).Debug("Send Class B downlink in Class A")
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("gateway_id", option.GatewayId),
+		attribute.Int64("score", int64(option.Score)),
+	)
+
+	gateway := r.getGateway(option.GatewayId)
+
+	// The RX2 option that belongs to an RX1 option is either sent as well, or
+	// scheduled as a second attempt that is only sent if RX1 is not
+	attempts := []*pb_broker.DownlinkMessage{downlink}
+	var rx2Downlink *pb_broker.DownlinkMessage
+	if r.alwaysRX2 || r.downlinkAttempts {
+		identifier, _ := r.gatewayDownlink(downlink)
+		if rx2, ok := r.rx2Options.get(identifier); ok {
+			rx2Copy := *downlink
+			rx2Copy.DownlinkOption = rx2
+			rx2Downlink = &rx2Copy
+			if !r.alwaysRX2 {
+				attempts = append(attempts, rx2Downlink)
+			}
+		}
+	}
+
+	if reason, err := r.scheduleDownlink(gateway, attempts...); err != nil {
+		return nackDownlink(reason), err
+	}
+
+	// Also send the downlink in RX2 if it is sent in RX1. The copy is checked
+	// and charged like any other downlink, and dropped if it is not allowed.
+	if r.alwaysRX2 && rx2Downlink != nil {
+		if reason, err := r.scheduleDownlink(gateway, rx2Downlink); err != nil {
+			r.Ctx.WithError(err).WithField("Reason", reason).Debug("Drop downlink in RX2")
+		}
+	}
+
+	fPort, hasFPort := fPortFromPayload(downlink.Payload)
+	res = acceptDownlink(option.GatewayId, option.GatewayConfig.Timestamp, option.GatewayConfig.Frequency)
+	res.FPort, res.MAC = uint32(fPort), hasFPort && fPort == 0
+	res.Window = window
+	return res, nil
+}
+
+// HandleDownlinkAttempts schedules the same downlink in multiple options of
+// one gateway, in order of preference (for example RX1 and RX2 with a different
+// data rate). The options that are not used are cancelled when the gateway
+// acknowledges the transmission of one of them.
+func (r *router) HandleDownlinkAttempts(downlinks ...*pb_broker.DownlinkMessage) (err error) {
+	_, span := r.Tracer(tracerName).Start(context.Background(), "router.HandleDownlinkAttempts")
+	span.SetAttributes(attribute.Int("attempts", len(downlinks)))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
+	if len(downlinks) == 0 {
+		return errors.NewErrInvalidArgument("Downlink attempts", "no downlinks")
+	}
+	for _, downlink := range downlinks {
+		if downlink.DownlinkOption == nil || downlink.DownlinkOption.GatewayConfig == nil {
+			return errors.NewErrInvalidArgument("Downlink", "no downlink option")
+		}
+		if downlink.DownlinkOption.GatewayId != downlinks[0].DownlinkOption.GatewayId {
+			return errors.NewErrInvalidArgument("Downlink attempts", "all downlinks must be for the same gateway")
+		}
+	}
+
+	span.SetAttributes(attribute.String("gateway_id", downlinks[0].DownlinkOption.GatewayId))
+
+	r.status.downlink.Mark(1)
+
+	if err := r.checkSafeMode(); err != nil {
+		return err
+	}
+
+	reason, err := r.scheduleDownlink(r.getGateway(downlinks[0].DownlinkOption.GatewayId), downlinks...)
+	if err != nil {
+		span.SetAttributes(attribute.String("nack_reason", string(reason)))
+	}
+	return err
+}
 
-	return r.getGateway(downlink.DownlinkOption.GatewayId).HandleDownlink(identifier, downlinkMessage)
+// checkDownlink checks if the gateway may send the downlink at the time of
+// scheduling
+func (r *router) checkDownlink(gateway *gateway.Gateway, downlink *pb_broker.DownlinkMessage) (NackReason, error) {
+	option := downlink.DownlinkOption
+	freq := option.GatewayConfig.Frequency
+	plan, err := r.getFrequencyPlan(getRegion(gateway, freq))
+	if err != nil {
+		return "", nil
+	}
+	duty, allowed := getGatewayDutyCycle(gateway, plan.Region, freq)
+	if !allowed || r.forbiddenReason(plan.Region, freq) != "" {
+		return NackForbidden, errors.NewErrInvalidArgument("Frequency", "transmissions forbidden")
+	}
+	// Reject downlinks that do not even fit the dwell time at the fastest data rate
+	if lorawan := option.GetProtocolConfig().GetLorawan(); lorawan != nil && plan.DwellTime > 0 {
+		if minTimeOnAir(plan, lorawan.Modulation, uint(len(downlink.Payload))) > plan.DwellTime {
+			return NackDwellTime, ErrDwellTimeExceeded
+		}
+	}
+	if plan.DutyCycle && duty > 0 && gateway.ChannelTx(freq) > duty {
+		return NackDutyCycle, errors.New("Duty cycle exceeded")
+	}
+	return "", nil
 }
 
-// buildDownlinkOption builds a DownlinkOption with default values
+// scheduleDownlink checks the downlink, charges its airtime and schedules it
+// on the gateway. Multiple attempts are the same downlink in different options
+// of the gateway, in order of preference, of which only one is transmitted.
+func (r *router) scheduleDownlink(gateway *gateway.Gateway, attempts ...*pb_broker.DownlinkMessage) (NackReason, error) {
+	for _, attempt := range attempts {
+		if reason, err := r.checkDownlink(gateway, attempt); err != nil {
+			return reason, err
+		}
+	}
+
+	// Any of the attempts may be transmitted, so the longest one is charged
+	charged := attempts[0]
+	for _, attempt := range attempts[1:] {
+		if downlinkTimeOnAir(attempt) > downlinkTimeOnAir(charged) {
+			charged = attempt
+		}
+	}
+
+	// The airtime is refunded if the downlink is not scheduled after all
+	if reason, err := r.chargeDownlink(gateway, charged); err != nil {
+		return reason, err
+	}
+
+	identifiers := make([]string, 0, len(attempts))
+	downlinkMessages := make([]*pb.DownlinkMessage, 0, len(attempts))
+	for _, attempt := range attempts {
+		identifier, downlinkMessage := r.gatewayDownlink(attempt)
+		identifiers = append(identifiers, identifier)
+		downlinkMessages = append(downlinkMessages, downlinkMessage)
+	}
+
+	var err error
+	if len(attempts) == 1 {
+		err = gateway.HandleDownlink(identifiers[0], downlinkMessages[0])
+	} else {
+		err = gateway.HandleDownlinkAttempts(identifiers, downlinkMessages)
+	}
+	if err != nil {
+		r.refundDownlink(gateway, charged)
+		return NackScheduleConflict, err
+	}
+
+	if devAddr, ok := devAddrFromPayload(charged.Payload); ok {
+		r.lastDownlink.set(devAddr, gateway.ID)
+	}
+
+	return "", nil
+}
+
+func (r *router) HandleTxAck(gatewayID string, identifier string, err error) error {
+	return r.getGateway(gatewayID).HandleTxAck(identifier, err)
+}
+
+// buildDownlinkOption builds a DownlinkOption with default values. The power is
+// the EIRP of the default TX power index; it is converted to conducted power by
+// the caller.
 func (r *router) buildDownlinkOption(gatewayID string, band band.FrequencyPlan) *pb_broker.DownlinkOption {
-	dataRate, _ := types.ConvertDataRate(band.DataRates[band.RX2DataRate])
-	return &pb_broker.DownlinkOption{
+	eirp, _ := band.GetTXPower(band.DefaultTXPowerIndex)
+	option := &pb_broker.DownlinkOption{
 		GatewayId: gatewayID,
 		ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{
-			Modulation: pb_lorawan.Modulation_LORA,
-			DataRate:   dataRate.String(),
 			CodingRate: "4/5",
 		}}},
 		GatewayConfig: &pb_gateway.TxConfiguration{
-			RfChain:               0,
-			PolarizationInversion: true,
-			Frequency:             uint64(band.RX2Frequency),
-			Power:                 int32(band.DefaultTXPower),
+			RfChain:   0,
+			Frequency: uint64(band.RX2Frequency),
+			Power:     eirp,
 		},
 	}
+	setDataRate(option, band.DataRates[band.RX2DataRate]) // RX2 may be LoRa or FSK
+	return option
+}
+
+// rx2PowerFloor raises the EIRP of the RX2 option so that the gateway sends it
+// with at least the given TX power, but never above the maximum EIRP of the
+// region. The result is an EIRP, to which the maximum TX power of the gateway
+// still applies.
+func rx2PowerFloor(gateway *gateway.Gateway, band band.FrequencyPlan, option *pb_broker.DownlinkOption, floor int32) int32 {
+	eirp := int32(math.Ceil(float64(floor) + gateway.AntennaGain - gateway.CableLoss))
+	max, _ := band.GetTXPower(0)
+	if option.GatewayConfig.Frequency == uint64(band.RX2Frequency) {
+		max, _ = band.GetRX2TXPower(0)
+	}
+	if eirp > max {
+		eirp = max
+	}
+	if option.GatewayConfig.Power > eirp {
+		return option.GatewayConfig.Power
+	}
+	return eirp
+}
+
+// setDataRate sets the data rate of the option and the gateway configuration
+// that depends on the modulation
+func setDataRate(option *pb_broker.DownlinkOption, dataRate lora.DataRate) error {
+	lorawan := option.ProtocolConfig.GetLorawan()
+	if err := lorawan.SetDataRate(dataRate); err != nil {
+		return err
+	}
+	option.GatewayConfig.PolarizationInversion = lorawan.Modulation == pb_lorawan.Modulation_LORA
+	option.GatewayConfig.FrequencyDeviation = uint32(lorawan.BitRate / 2)
+	return nil
 }
 
 func (r *router) buildDownlinkOptions(uplink *pb.UplinkMessage, isActivation bool, gateway *gateway.Gateway) (downlinkOptions []*pb_broker.DownlinkOption) {
-	var options []*pb_broker.DownlinkOption
+	rx1, rx2, downlinkOptions, drops := r.scoreDownlinkOptions(uplink, isActivation, gateway, true)
+
+	// Remember the RX2 option, so that it can be used together with RX1
+	if (r.alwaysRX2 || r.downlinkAttempts) && rx1 != nil && rx2 != nil && rx2.Score < 1000 {
+		r.rx2Options.add(rx1.Identifier, rx2)
+	}
+
+	// Add router ID to downlink options
+	if r.Component != nil && r.Component.Identity != nil {
+		for _, option := range downlinkOptions {
+			option.Identifier = r.Component.Identity.Id + ":" + option.Identifier
+		}
+	}
+
+	// Remember the window of the options for the result and the span of the
+	// scheduling decision
+	for _, option := range downlinkOptions {
+		window := WindowRX2
+		if option == rx1 {
+			window = WindowRX1
+		}
+		r.optionTraces.add(option.Identifier, window, drops)
+	}
+
+	return
+}
 
-	gatewayStatus, _ := gateway.Status.Get() // This just returns empty if non-existing
+// scoreDownlinkOptions builds and scores the RX1 and RX2 options for the uplink,
+// and returns the options that can be used and the reasons why the others were
+// dropped. If book is false, the options are
+// scored against the schedule of the gateway, but not booked in it; they do not
+// have an identifier then.
+func (r *router) scoreDownlinkOptions(uplink *pb.UplinkMessage, isActivation bool, gateway *gateway.Gateway, book bool) (rx1, rx2 *pb_broker.DownlinkOption, downlinkOptions []*pb_broker.DownlinkOption, drops []string) {
+	options := make([]*pb_broker.DownlinkOption, 0, 2) // RX2 and RX1
+
+	if gateway.RXOnly {
+		return // The gateway can not transmit
+	}
 
 	lorawanMetadata := uplink.ProtocolMetadata.GetLorawan()
 	if lorawanMetadata == nil {
 		return // We can't handle any other protocols than LoRaWAN yet
 	}
 
-	region := gatewayStatus.Region
-	if region == "" {
-		region = band.Guess(uplink.GatewayMetadata.Frequency)
-	}
-	band, err := band.Get(region)
+	region := getRegion(gateway, uplink.GatewayMetadata.Frequency)
+	band, err := r.getFrequencyPlan(region)
 	if err != nil {
 		return // We can't handle this region
 	}
@@ -117,8 +421,17 @@ func (r *router) buildDownlinkOptions(uplink *pb.UplinkMessage, isActivation boo
 	// Configuration for RX2
 	buildRX2 := func() (*pb_broker.DownlinkOption, error) {
 		option := r.buildDownlinkOption(gateway.ID, band)
-		if region == "EU_863_870" {
-			option.GatewayConfig.Power = 27 // The EU RX2 frequency allows up to 27dBm
+		if isSaturated(gateway, band, option.GatewayConfig.Frequency) {
+			// Fall back to the first RX2 frequency that is not saturated
+			for _, freq := range r.rx2Frequencies[region] {
+				if !isSaturated(gateway, band, uint64(freq)) {
+					option.GatewayConfig.Frequency = uint64(freq)
+					break
+				}
+			}
+		}
+		if option.GatewayConfig.Frequency == uint64(band.RX2Frequency) {
+			option.GatewayConfig.Power, _ = band.GetRX2TXPower(band.DefaultTXPowerIndex)
 		}
 		if isActivation {
 			option.GatewayConfig.Timestamp = uplink.GatewayMetadata.Timestamp + uint32(band.JoinAcceptDelay2/1000)
@@ -126,11 +439,22 @@ func (r *router) buildDownlinkOptions(uplink *pb.UplinkMessage, isActivation boo
 			option.GatewayConfig.Timestamp = uplink.GatewayMetadata.Timestamp + uint32(band.ReceiveDelay2/1000)
 		}
 		option.ProtocolConfig.GetLorawan().CodingRate = lorawanMetadata.CodingRate
+		if r.rx2PowerFloor != 0 {
+			option.GatewayConfig.Power = rx2PowerFloor(gateway, band, option, r.rx2PowerFloor)
+		}
+		power, err := gateway.TXPower(option.GatewayConfig.Power)
+		if err != nil {
+			return nil, err
+		}
+		option.GatewayConfig.Power = power
 		return option, nil
 	}
 
-	if option, err := buildRX2(); err == nil {
-		options = append(options, option)
+	rx2, err = buildRX2()
+	if err == nil {
+		options = append(options, rx2)
+	} else {
+		drops = append(drops, err.Error())
 	}
 
 	// Configuration for RX1
@@ -143,62 +467,130 @@ func (r *router) buildDownlinkOptions(uplink *pb.UplinkMessage, isActivation boo
 		}
 		option.ProtocolConfig.GetLorawan().CodingRate = lorawanMetadata.CodingRate
 
+		// The gateway needs some time to switch from RX to TX after the uplink,
+		// which may be taken from the tolerance of the RX1 window
+		tolerance := band.RX1WindowTolerance
+		if delay := time.Duration(option.GatewayConfig.Timestamp-uplink.GatewayMetadata.Timestamp) * time.Microsecond; delay < r.switchGuard {
+			if r.switchGuard-delay > tolerance {
+				return nil, errors.NewErrInvalidArgument("RX1", "starts within the RX to TX switch guard")
+			}
+			option.GatewayConfig.Timestamp = uplink.GatewayMetadata.Timestamp + uint32(r.switchGuard/time.Microsecond)
+			tolerance -= r.switchGuard - delay
+		}
+
 		freq, err := band.GetRX1Frequency(int(uplink.GatewayMetadata.Frequency))
 		if err != nil {
 			return nil, err
 		}
 		option.GatewayConfig.Frequency = uint64(freq)
 
+		// Drop RX1 if transmissions on its frequency are forbidden, RX2 remains
+		if reason := r.forbiddenReason(region, uint64(freq)); reason != "" {
+			gateway.Ctx.WithFields(log.Fields{
+				"Frequency": freq,
+				"Reason":    reason,
+			}).Debug("Drop RX1 on forbidden frequency")
+			return nil, errors.NewErrInvalidArgument("RX1 frequency", reason)
+		}
+
 		upDR, err := band.GetDataRate(dataRate)
 		if err != nil {
 			return nil, err
 		}
-		downDR, err := band.GetRX1DataRate(upDR, 0)
+		downDR, err := band.GetRX1DataRate(upDR)
 		if err != nil {
 			return nil, err
 		}
 
-		if err := option.ProtocolConfig.GetLorawan().SetDataRate(band.DataRates[downDR]); err != nil {
+		// Use slower data rates if the gateway has a maximum RX1 data rate, but
+		// only those that are legal in RX1 for the uplink data rate
+		if maxDR := gateway.MaxRX1DataRate; maxDR != nil {
+			rx1DRs, err := band.GetRX1DataRates(upDR)
+			if err != nil {
+				return nil, err
+			}
+			for _, downDR = range rx1DRs {
+				if band.DataRates[downDR].Modulation != lora.LoRaModulation || band.DataRates[downDR].SpreadFactor >= int(maxDR.SpreadingFactor) {
+					break
+				}
+			}
+		}
+
+		if err := setDataRate(option, band.DataRates[downDR]); err != nil {
+			return nil, err
+		}
+		power, err := gateway.TXPower(option.GatewayConfig.Power)
+		if err != nil {
 			return nil, err
 		}
-		option.GatewayConfig.FrequencyDeviation = uint32(option.ProtocolConfig.GetLorawan().BitRate / 2)
+		option.GatewayConfig.Power = power
+
+		// Resolve schedule conflicts with what is left of the tolerance
+		if tolerance > 0 {
+			length := uint32(computeTimeOnAir(option.ProtocolConfig.GetLorawan(), 51+13) / 1000)
+			option.GatewayConfig.Timestamp = shiftTimestamp(gateway.Schedule, option.GatewayConfig.Timestamp, length, tolerance)
+		}
 
 		return option, nil
 	}
 
-	if option, err := buildRX1(); err == nil {
-		options = append(options, option)
+	rx1, err = buildRX1()
+	if err == nil {
+		options = append(options, rx1)
+	} else {
+		drops = append(drops, err.Error())
 	}
 
-	computeDownlinkScores(gateway, uplink, options)
+	computeDownlinkScores(gateway, uplink, band, options, r.snrDominance, book)
 
+	downlinkOptions = make([]*pb_broker.DownlinkOption, 0, len(options))
 	for _, option := range options {
-		// Add router ID to downlink option
-		if r.Component != nil && r.Component.Identity != nil {
-			option.Identifier = fmt.Sprintf("%s:%s", r.Component.Identity.Id, option.Identifier)
+		// Filter all illegal options
+		if option.Score >= 1000 {
+			drops = append(drops, dropNotAllowed)
+			continue
 		}
 
-		// Filter all illegal options
-		if option.Score < 1000 {
-			downlinkOptions = append(downlinkOptions, option)
+		// Filter all options that are very unlikely to succeed
+		if r.maxScore != 0 && option.Score > r.maxScore {
+			drops = append(drops, dropMaxScore)
+			continue
 		}
+
+		downlinkOptions = append(downlinkOptions, option)
 	}
 
+	r.applyStickiness(uplink, gateway.ID, downlinkOptions)
+	r.applyTxAckBonus(gateway, downlinkOptions)
+
 	return
 }
 
+// bookDownlinkOption books an option that was scored without booking in the
+// schedule of the gateway, and sets its identifier
+func (r *router) bookDownlinkOption(gateway *gateway.Gateway, option *pb_broker.DownlinkOption) {
+	length := uint32(computeTimeOnAir(option.GetProtocolConfig().GetLorawan(), 51+13) / 1000)
+	option.Identifier, _ = gateway.Schedule.GetOption(option.GatewayConfig.Timestamp, length)
+	if r.Component != nil && r.Component.Identity != nil {
+		option.Identifier = r.Component.Identity.Id + ":" + option.Identifier
+	}
+}
+
 // Calculating the score for each downlink option; lower is better, 0 is best
 // If a score is over 1000, it may should not be used as feasible option.
 // TODO: The weights of these parameters should be optimized. I'm sure someone
 // can do some computer simulations to find the right values.
-func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, options []*pb_broker.DownlinkOption) {
-	gatewayStatus, _ := gateway.Status.Get() // This just returns empty if non-existing
-
-	region := gatewayStatus.Region
-	if region == "" {
-		region = band.Guess(uplink.GatewayMetadata.Frequency)
-	}
-
+//
+// The snrDominance (between -1 and 1) shifts the weight of the signal score
+// between SNR and RSSI. At 0, both contribute equally; at 1, only SNR counts and
+// at -1, only RSSI counts. The signal score stays between 0 and 20, so the
+// dominance does not change the weight of the signal relative to the other
+// parts of the score.
+//
+// If book is true, the options are booked in the schedule of the gateway and
+// get an identifier. Otherwise they are only scored against the schedule.
+func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, plan band.FrequencyPlan, options []*pb_broker.DownlinkOption, snrDominance float64, book bool) {
+	snrDominance = math.Max(-1, math.Min(snrDominance, 1))
 	gatewayRx, _ := gateway.Utilization.Get()
 	for _, option := range options {
 
@@ -209,24 +601,8 @@ func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, o
 			continue
 		}
 
-		var time time.Duration
-
-		if lorawan.Modulation == pb_lorawan.Modulation_LORA {
-			// Calculate max ToA
-			time, _ = toa.ComputeLoRa(
-				51+13, // Max MACPayload plus LoRaWAN header, TODO: What is the length we should use?
-				lorawan.DataRate,
-				lorawan.CodingRate,
-			)
-		}
-
-		if lorawan.Modulation == pb_lorawan.Modulation_FSK {
-			// Calculate max ToA
-			time, _ = toa.ComputeFSK(
-				51+13, // Max MACPayload plus LoRaWAN header, TODO: What is the length we should use?
-				int(lorawan.BitRate),
-			)
-		}
+		// Calculate max ToA
+		time := computeTimeOnAir(lorawan, 51+13) // Max MACPayload plus LoRaWAN header, TODO: What is the length we should use?
 
 		// Invalid if time is zero
 		if time == 0 {
@@ -234,58 +610,61 @@ func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, o
 			continue
 		}
 
+		// Invalid if even the smallest frame exceeds the dwell time
+		if plan.DwellTime > 0 && computeTimeOnAir(lorawan, minFrameSize) > plan.DwellTime {
+			option.Score = 1000
+			continue
+		}
+
 		timeScore := math.Min(time.Seconds()*5, 10) // 2 seconds will be 10 (max)
 
 		signalScore := 0.0 // Between 0 and 20 (lower is better)
 		{
 			// Prefer high SNR
 			if uplink.GatewayMetadata.Snr < 5 {
-				signalScore += 10
+				signalScore += 10 * (1 + snrDominance)
 			}
 			// Prefer good RSSI
-			signalScore += math.Min(float64(uplink.GatewayMetadata.Rssi*-0.1), 10)
+			signalScore += math.Min(float64(uplink.GatewayMetadata.Rssi*-0.1), 10) * (1 - snrDominance)
 		}
 
 		utilizationScore := 0.0 // Between 0 and 40 (lower is better) will be over 100 if forbidden
 		{
-			// Avoid gateways that do more Rx
-			utilizationScore += math.Min(gatewayRx*50, 20) / 2 // 40% utilization = 10 (max)
+			// Avoid gateways that do more Rx (unless they can receive while transmitting)
+			if !gateway.FullDuplex {
+				utilizationScore += math.Min(gatewayRx*50, 20) / 2 // 40% utilization = 10 (max)
+			}
 
 			// Avoid busy channels
 			freq := option.GatewayConfig.Frequency
-			channelRx, channelTx := gateway.Utilization.GetChannel(freq)
+			channelRx, _ := gateway.Utilization.GetChannel(freq)
+			channelTx := gateway.ChannelTx(freq)
+			if gateway.FullDuplex {
+				channelRx = 0
+			}
 			utilizationScore += math.Min((channelTx+channelRx)*200, 20) / 2 // 10% utilization = 10 (max)
 
-			// European Duty Cycle
-			if region == "EU_863_870" {
-				var duty float64
-				switch {
-				case freq >= 863000000 && freq < 868000000:
-					duty = 0.01 // g 863.0 – 868.0 MHz 1%
-				case freq >= 868000000 && freq < 868600000:
-					duty = 0.01 // g1 868.0 – 868.6 MHz 1%
-				case freq >= 868700000 && freq < 869200000:
-					duty = 0.001 // g2 868.7 – 869.2 MHz 0.1%
-				case freq >= 869400000 && freq < 869650000:
-					duty = 0.1 // g3 869.4 – 869.65 MHz 10%
-				case freq >= 869700000 && freq < 870000000:
-					duty = 0.01 // g4 869.7 – 870.0 MHz 1%
-				default:
-					utilizationScore += 100 // Transmissions on this frequency are forbidden
-				}
+			// Duty Cycle
+			duty, allowed := getGatewayDutyCycle(gateway, plan.Region, freq)
+			if !allowed {
+				utilizationScore += 100 // Transmissions on this frequency are forbidden
+			}
+			if plan.DutyCycle && duty > 0 {
 				if channelTx > duty {
 					utilizationScore += 100 // Transmissions on this frequency are forbidden
 				}
-				if duty > 0 {
-					utilizationScore += math.Min(time.Seconds()/duty/100, 20) // Impact on duty-cycle (in order to prefer RX2 for SF9BW125)
-				}
+				utilizationScore += math.Min(time.Seconds()/duty/100, 20) // Impact on duty-cycle (in order to prefer RX2 for SF9BW125)
 			}
 		}
 
 		scheduleScore := 0.0 // Between 0 and 30 (lower is better) will be over 100 if forbidden
 		{
-			id, conflicts := gateway.Schedule.GetOption(option.GatewayConfig.Timestamp, uint32(time/1000))
-			option.Identifier = id
+			var conflicts uint
+			if book {
+				option.Identifier, conflicts = gateway.Schedule.GetOption(option.GatewayConfig.Timestamp, uint32(time/1000))
+			} else {
+				conflicts = gateway.Schedule.Conflicts(option.GatewayConfig.Timestamp, uint32(time/1000))
+			}
 			if conflicts >= 100 {
 				scheduleScore += 100
 			} else {
@@ -296,3 +675,45 @@ func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, o
 		option.Score = uint32((timeScore + signalScore + utilizationScore + scheduleScore) * 10)
 	}
 }
+
+// lowerScores lowers the score of the options by the given amount, without
+// going below zero
+func lowerScores(options []*pb_broker.DownlinkOption, by uint32) {
+	for _, option := range options {
+		if option.Score > by {
+			option.Score -= by
+		} else {
+			option.Score = 0
+		}
+	}
+}
+
+// shiftStep is the step in which downlinks are shifted to resolve schedule conflicts
+const shiftStep = time.Millisecond
+
+// shiftTimestamp returns the earliest timestamp within the tolerance after the
+// given timestamp at which a downlink of the given length (in microseconds) does
+// not conflict with the schedule. If there is no such timestamp, the given
+// timestamp is returned.
+func shiftTimestamp(schedule gateway.Schedule, timestamp uint32, length uint32, tolerance time.Duration) uint32 {
+	if schedule.Conflicts(timestamp, length) == 0 {
+		return timestamp
+	}
+	for shift := shiftStep; shift <= tolerance; shift += shiftStep {
+		shifted := timestamp + uint32(shift/time.Microsecond)
+		if schedule.Conflicts(shifted, length) == 0 {
+			return shifted
+		}
+	}
+	return timestamp
+}
+
+// minFrameSize is the size of the smallest LoRaWAN frame: MHDR, FHDR and MIC
+const minFrameSize = 1 + 7 + 4
+
+// computeTimeOnAir calculates the time on air of a payload of the given size
+// (in bytes). It returns zero if the time on air can not be calculated.
+func computeTimeOnAir(lorawan *pb_lorawan.TxConfiguration, payloadSize uint) time.Duration {
+	t, _ := gateway.TimeOnAir(lorawan, payloadSize)
+	return t
+}
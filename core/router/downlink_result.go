@@ -0,0 +1,77 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+
+// NackReason is the reason why a downlink was not scheduled
+type NackReason string
+
+// Reasons for not scheduling a downlink
+const (
+	NackInvalid            NackReason = "invalid downlink"
+	NackQuotaExceeded      NackReason = "airtime quota exceeded"
+	NackClassQuotaExceeded NackReason = "airtime quota of priority class exceeded"
+	NackForbidden          NackReason = "transmissions on frequency forbidden"
+	NackDutyCycle          NackReason = "duty cycle exceeded"
+	NackDwellTime          NackReason = "dwell time exceeded"
+	NackScheduleConflict   NackReason = "could not schedule"
+	NackDownlinksDisabled  NackReason = "downlinks disabled"
+)
+
+// Windows in which a downlink is sent
+const (
+	WindowRX1      = "RX1"
+	WindowRX2      = "RX2"
+	WindowPingSlot = "PING_SLOT"
+)
+
+// DownlinkResult is the result of scheduling a downlink. If the downlink is
+// accepted, it contains the window in which it will be sent. If not, it
+// contains the reason why it is not accepted.
+type DownlinkResult struct {
+	Accepted bool
+
+	// GatewayID of the gateway that will send the downlink
+	GatewayID string
+
+	// Window (RX1, RX2 or PING_SLOT) in which the downlink will be sent. It
+	// is empty if the option of the downlink is unknown to the router.
+	Window string
+
+	// Timestamp and Frequency of the window in which the downlink will be sent
+	Timestamp uint32
+	Frequency uint64
+
+	// FPort of the downlink frame (0 if the frame has no FPort). MAC is set if
+	// the frame is a MAC-only downlink on FPort 0; those are prioritized.
+	FPort uint32
+	MAC   bool
+
+	// NackReason is set if the downlink is not accepted
+	NackReason NackReason
+}
+
+func acceptDownlink(gatewayID string, timestamp uint32, frequency uint64) *DownlinkResult {
+	return &DownlinkResult{Accepted: true, GatewayID: gatewayID, Timestamp: timestamp, Frequency: frequency}
+}
+
+func nackDownlink(reason NackReason) *DownlinkResult {
+	return &DownlinkResult{NackReason: reason}
+}
+
+// brokerResult returns the result as it is sent to the broker of the downlink
+func (res *DownlinkResult) brokerResult(downlink *pb_broker.DownlinkMessage) *pb_broker.DownlinkResult {
+	return &pb_broker.DownlinkResult{
+		TraceId:    downlink.TraceId,
+		AppId:      downlink.AppId,
+		DevId:      downlink.DevId,
+		Accepted:   res.Accepted,
+		GatewayId:  res.GatewayID,
+		Window:     res.Window,
+		Timestamp:  res.Timestamp,
+		Frequency:  res.Frequency,
+		NackReason: string(res.NackReason),
+	}
+}
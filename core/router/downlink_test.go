@@ -4,6 +4,7 @@
 package router
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -13,10 +14,20 @@ import (
 	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/band"
 	"github.com/TheThingsNetwork/ttn/core/component"
 	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/apex/log"
+	"github.com/brocaar/lorawan"
 	. "github.com/smartystreets/assertions"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
 )
 
 // newReferenceDownlink returns a default uplink message
@@ -49,7 +60,7 @@ func TestHandleDownlink(t *testing.T) {
 
 	gtwID := "eui-0102030405060708"
 	id, _ := r.getGateway(gtwID).Schedule.GetOption(0, 10*1000)
-	err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+	_, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
 		Payload: []byte{},
 		DownlinkOption: &pb_broker.DownlinkOption{
 			GatewayId:      gtwID,
@@ -484,3 +495,1654 @@ func TestComputeDownlinkScores(t *testing.T) {
 	a.So(testSubject1Score, ShouldBeGreaterThan, refScore) // Scheduling conflict with RX1
 	a.So(testSubject2Score, ShouldEqual, refScore)         // No scheduling conflicts
 }
+
+func TestDownlinkStickiness(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestDownlinkStickiness"),
+		},
+		gateways:   map[string]*gateway.Gateway{},
+		stickiness: 20,
+	}
+	r.InitStatus()
+
+	gtwA := r.getGateway("eui-0102030405060708")
+	gtwA.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+	gtwB := r.getGateway("eui-0807060504030201")
+	gtwB.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	// The first uplink is received best by gateway A
+	upA, upB := newReferenceUplink(), newReferenceUplink()
+	upB.GatewayMetadata.Rssi = -30
+	optionsA := r.buildDownlinkOptions(upA, false, gtwA)
+	optionsB := r.buildDownlinkOptions(upB, false, gtwB)
+	a.So(optionsA[1].Score, ShouldBeLessThan, optionsB[1].Score)
+
+	_, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        upA.Payload,
+		DownlinkOption: optionsA[1],
+	})
+	a.So(err, ShouldBeNil)
+
+	// The second uplink is received slightly better by gateway B
+	upA, upB = newReferenceUplink(), newReferenceUplink()
+	upA.GatewayMetadata.Timestamp, upB.GatewayMetadata.Timestamp = 10000000, 10000000
+	upA.GatewayMetadata.Rssi = -30
+	optionsA = r.buildDownlinkOptions(upA, false, gtwA)
+	optionsB = r.buildDownlinkOptions(upB, false, gtwB)
+	a.So(optionsA[1].Score, ShouldBeLessThan, optionsB[1].Score)
+
+	_, err = r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        upA.Payload,
+		DownlinkOption: optionsA[1],
+	})
+	a.So(err, ShouldBeNil)
+
+	// Without stickiness, gateway B would have been preferred
+	r.stickiness = 0
+	upA, upB = newReferenceUplink(), newReferenceUplink()
+	upA.GatewayMetadata.Timestamp, upB.GatewayMetadata.Timestamp = 20000000, 20000000
+	upA.GatewayMetadata.Rssi = -30
+	optionsA = r.buildDownlinkOptions(upA, false, gtwA)
+	optionsB = r.buildDownlinkOptions(upB, false, gtwB)
+	a.So(optionsA[1].Score, ShouldBeGreaterThan, optionsB[1].Score)
+}
+
+func TestLastDownlinkGatewaysTimeout(t *testing.T) {
+	a := New(t)
+
+	var l lastDownlinkGateways
+	devA, devB := types.DevAddr{1, 2, 3, 4}, types.DevAddr{4, 3, 2, 1}
+	l.set(devA, "eui-0102030405060708")
+
+	gatewayID, ok := l.get(devA)
+	a.So(ok, ShouldBeTrue)
+	a.So(gatewayID, ShouldEqual, "eui-0102030405060708")
+
+	// Expired gateways are forgotten
+	last := l.gateways[devA]
+	last.sentAt = time.Now().Add(-2 * lastDownlinkTimeout)
+	l.gateways[devA] = last
+	_, ok = l.get(devA)
+	a.So(ok, ShouldBeFalse)
+
+	// And removed when the next downlink is set
+	l.sweptAt = time.Time{}
+	l.set(devB, "eui-0807060504030201")
+	a.So(l.gateways, ShouldHaveLength, 1)
+	a.So(l.gateways, ShouldContainKey, devB)
+}
+
+func TestDownlinkTxAckBonus(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestDownlinkTxAckBonus"),
+		},
+		gateways:   map[string]*gateway.Gateway{},
+		txAckBonus: 20,
+	}
+
+	gtwA := r.getGateway("eui-0102030405060708")
+	gtwA.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+	gtwB := r.getGateway("eui-0807060504030201")
+	gtwB.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	// Without TxAcks, both gateways score equally
+	optionsA := r.buildDownlinkOptions(newReferenceUplink(), false, gtwA)
+	optionsB := r.buildDownlinkOptions(newReferenceUplink(), false, gtwB)
+	a.So(optionsA[1].Score, ShouldEqual, optionsB[1].Score)
+
+	// Gateway A transmitted all its recent downlinks, gateway B frequently did
+	// not. The gateways send their TxAcks over the TxAck stream.
+	viper.Set("router.skip-verify-gateway-token", true)
+	defer viper.Set("router.skip-verify-gateway-token", false)
+	rpc := &routerRPC{router: r}
+	acksA, err := rpc.getTxAck(metadata.Pairs("id", gtwA.ID))
+	a.So(err, ShouldBeNil)
+	acksB, err := rpc.getTxAck(metadata.Pairs("id", gtwB.ID))
+	a.So(err, ShouldBeNil)
+	for i := 0; i < 10; i++ {
+		acksA <- &pb.TxAcknowledgment{Identifier: "ack"}
+		if i%2 == 0 {
+			acksB <- &pb.TxAcknowledgment{Identifier: "ack"}
+		} else {
+			acksB <- &pb.TxAcknowledgment{Identifier: "ack", Error: pb.TxAcknowledgment_COLLISION_PACKET}
+		}
+	}
+	close(acksA)
+	close(acksB)
+	time.Sleep(10 * time.Millisecond)
+
+	optionsA = r.buildDownlinkOptions(newReferenceUplink(), false, gtwA)
+	optionsB = r.buildDownlinkOptions(newReferenceUplink(), false, gtwB)
+	a.So(optionsA[1].Score, ShouldBeLessThan, optionsB[1].Score)
+	a.So(optionsA[0].Score, ShouldBeLessThan, optionsB[0].Score)
+}
+
+func TestDownlinkTXPowerClamp(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+
+	gtw, up := newReferenceGateway(t, "EU_863_870"), newReferenceUplink()
+	gtw.AntennaGain = 10
+	gtw.CableLoss = 1
+	options := r.buildDownlinkOptions(up, false, gtw)
+	a.So(options, ShouldHaveLength, 2)
+	a.So(options[1].GatewayConfig.Power, ShouldEqual, 7)  // 16 - 10 + 1
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 20) // 29 - 10 + 1
+
+	// An extreme antenna gain is clamped to the minimum TX power
+	gtw, up = newReferenceGateway(t, "EU_863_870"), newReferenceUplink()
+	gtw.AntennaGain = 50
+	gtw.MinTXPower = 2
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options, ShouldHaveLength, 2)
+	a.So(options[1].GatewayConfig.Power, ShouldEqual, 2)
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 2)
+}
+
+func TestDownlinkTXPowerIndex(t *testing.T) {
+	a := New(t)
+
+	plan, _ := band.Get("EU_863_870")
+	gtw := newReferenceGateway(t, "EU_863_870")
+	gtw.AntennaGain = 3
+	gtw.MinTXPower = -10
+
+	// Conducted power is MaxEIRP - 2*txPowerIndex - antennaGain
+	for index, power := range []int32{13, 11, 9, 7, 5, 3, 1, -1} {
+		eirp, err := plan.GetTXPower(index)
+		a.So(err, ShouldBeNil)
+		conducted, err := gtw.TXPower(eirp)
+		a.So(err, ShouldBeNil)
+		a.So(conducted, ShouldEqual, power)
+	}
+}
+
+func TestDownlinkDefaultTXPowerIndex(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options[1].GatewayConfig.Power, ShouldEqual, 14)
+
+	// Each index lowers the power by 2 dB
+	r = &router{txPowerIndices: map[string][]int{"EU_863_870": {2}}}
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 23)
+	a.So(options[1].GatewayConfig.Power, ShouldEqual, 10)
+
+	// Invalid indices are not used
+	r = &router{txPowerIndices: map[string][]int{"EU_863_870": {8}}}
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldBeEmpty)
+}
+
+func TestDownlinkRX2PowerFloor(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestDownlinkRX2PowerFloor"),
+		},
+		gateways:            map[string]*gateway.Gateway{},
+		rx2PowerFloor:       12,
+		txPowerIndices:      map[string][]int{"AS_923": {3}},
+		powerErrorThreshold: 1,
+	}
+	r.InitStatus()
+
+	// Even for a strong uplink, RX2 uses at least the floor
+	up := newReferenceUplink()
+	up.GatewayMetadata.Rssi = -20
+	up.GatewayMetadata.Snr = 10
+	gtw := newReferenceGateway(t, "AS_923")
+	options := r.buildDownlinkOptions(up, false, gtw)
+	a.So(options, ShouldNotBeEmpty)
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 12) // Instead of 10 dBm EIRP minus the antenna gain
+
+	// The floor is limited by the maximum EIRP of the region
+	r.rx2PowerFloor = 20
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 14) // 16 dBm EIRP minus the antenna gain
+
+	// A higher RX2 power is not lowered to the floor
+	gtw = newReferenceGateway(t, "EU_863_870")
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 27)
+	a.So(options[1].GatewayConfig.Power, ShouldEqual, 14)
+
+	// The floor does not override the maximum TX power that the gateway learned
+	gtw = r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+	rx2 := r.buildDownlinkOptions(up, false, gtw)[0]
+	_, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        newReferenceDownlink().Payload,
+		DownlinkOption: rx2,
+	})
+	a.So(err, ShouldBeNil)
+	r.HandleTxAck(gtw.ID, rx2.Identifier, (&pb.TxAcknowledgment{Error: pb.TxAcknowledgment_TX_POWER}).Err())
+	max, ok := gtw.MaxTXPower()
+	a.So(ok, ShouldBeTrue)
+	a.So(max, ShouldEqual, 26)
+
+	r.rx2PowerFloor = 27
+	up.GatewayMetadata.Timestamp = 10000000
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 26)
+}
+
+func TestUplinkBuildDownlinkOptionsSubBands(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		subBands: map[string][]int{"US_902_928": {2}},
+	}
+
+	gtw := newReferenceGateway(t, "US_902_928")
+
+	// Uplink channels in sub-band 2 map to RX1
+	ttnUSFrequencies := map[uint64]uint64{
+		903900000: 923300000,
+		904100000: 923900000,
+		905300000: 927500000,
+		904600000: 923900000, // 500 kHz channel of sub-band 2
+	}
+	for upFreq, downFreq := range ttnUSFrequencies {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Frequency = upFreq
+		if upFreq == 904600000 {
+			up.ProtocolMetadata.GetLorawan().DataRate = "SF8BW500"
+		}
+		options := r.buildDownlinkOptions(up, false, gtw)
+		a.So(options, ShouldHaveLength, 2)
+		a.So(options[1].GatewayConfig.Frequency, ShouldEqual, downFreq)
+	}
+
+	// Uplink channels outside sub-band 2 only use RX2
+	for _, upFreq := range []uint64{902300000, 903700000, 905500000} {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Frequency = upFreq
+		options := r.buildDownlinkOptions(up, false, gtw)
+		a.So(options, ShouldHaveLength, 1)
+		a.So(options[0].GatewayConfig.Frequency, ShouldEqual, 923300000)
+	}
+}
+
+func TestUplinkBuildDownlinkOptionsRX2Fallback(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		rx2Frequencies: map[string][]int{"EU_863_870": {869525000, 869700000}},
+	}
+
+	saturatedGateway := func() *gateway.Gateway {
+		gtw := newReferenceGateway(t, "EU_863_870")
+		for i := 0; i < 20; i++ {
+			downlink := newReferenceDownlink()
+			downlink.GatewayConfiguration.Frequency = 869525000
+			gtw.Utilization.AddTx(downlink)
+		}
+		gtw.Utilization.Tick()
+		return gtw
+	}
+
+	// Primary RX2 frequency is used if it is not saturated
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 2)
+	a.So(options[0].GatewayConfig.Frequency, ShouldEqual, 869525000)
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 27)
+
+	// Next RX2 frequency is used if the primary RX2 frequency is saturated
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, saturatedGateway())
+	a.So(options, ShouldHaveLength, 2)
+	a.So(options[0].GatewayConfig.Frequency, ShouldEqual, 869700000)
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 14)
+
+	// Without fallback frequencies, the RX2 option is dropped
+	r = &router{}
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, saturatedGateway())
+	a.So(options, ShouldHaveLength, 1)
+	a.So(options[0].GatewayConfig.Frequency, ShouldEqual, 868100000)
+}
+
+func TestComputeDownlinkScoresDutyCycleDwellTime(t *testing.T) {
+	a := New(t)
+
+	saturatedGateway := func() *gateway.Gateway {
+		gtw := newReferenceGateway(t, "EU_863_870")
+		for i := 0; i < 5; i++ {
+			gtw.Utilization.AddTx(newReferenceDownlink())
+		}
+		gtw.Utilization.Tick()
+		return gtw
+	}
+
+	slowUplink := func() *pb.UplinkMessage {
+		up := newReferenceUplink()
+		up.ProtocolMetadata.GetLorawan().DataRate = "SF12BW125"
+		return up
+	}
+
+	// The default EU plan limits the duty cycle but not the dwell time
+	r := &router{}
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, saturatedGateway())
+	a.So(options, ShouldHaveLength, 1) // RX1 Removed
+	options = r.buildDownlinkOptions(slowUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 2)
+
+	// Plan without duty cycle, but with dwell time
+	plan, _ := band.Get("EU_863_870")
+	plan.DutyCycle = false
+	plan.DwellTime = 400 * time.Millisecond
+	r = &router{
+		frequencyPlans: map[string]band.FrequencyPlan{"EU_863_870": plan},
+	}
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, saturatedGateway())
+	a.So(options, ShouldHaveLength, 2) // No duty-cycle drops
+	options = r.buildDownlinkOptions(slowUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 1) // RX1 (SF12) Removed
+	a.So(options[0].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF9BW125")
+}
+
+func TestUplinkBuildDownlinkOptionsMaxScore(t *testing.T) {
+	a := New(t)
+
+	r := &router{maxScore: 250}
+
+	// The options of a good gateway are accepted
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldNotBeEmpty)
+
+	// A poor gateway has a lot of Rx and a bad signal
+	gtw := newReferenceGateway(t, "EU_863_870")
+	for i := 0; i < 100; i++ {
+		gtw.Utilization.AddRx(newReferenceUplink())
+	}
+	gtw.Utilization.Tick()
+	uplink := newReferenceUplink()
+	uplink.GatewayMetadata.Snr = -10
+	uplink.GatewayMetadata.Rssi = -120
+
+	options = r.buildDownlinkOptions(uplink, false, gtw)
+	a.So(options, ShouldBeEmpty)
+
+	// Without threshold, the options of the poor gateway are accepted
+	r = &router{}
+	options = r.buildDownlinkOptions(uplink, false, gtw)
+	a.So(options, ShouldNotBeEmpty)
+}
+
+func TestComputeDownlinkScoresFullDuplex(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+	refScore := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))[1].Score
+
+	busyGateway := func(fullDuplex bool) *gateway.Gateway {
+		gtw := newReferenceGateway(t, "EU_863_870")
+		gtw.FullDuplex = fullDuplex
+		gtw.Utilization.AddRx(newReferenceUplink())
+		gtw.Utilization.Tick()
+		return gtw
+	}
+
+	// Half-duplex gateway is penalized for Rx on the same channel
+	a.So(r.buildDownlinkOptions(newReferenceUplink(), false, busyGateway(false))[1].Score, ShouldBeGreaterThan, refScore)
+
+	// Full-duplex gateway is not
+	a.So(r.buildDownlinkOptions(newReferenceUplink(), false, busyGateway(true))[1].Score, ShouldEqual, refScore)
+}
+
+func TestComputeDownlinkScoresSNRDominance(t *testing.T) {
+	a := New(t)
+
+	// Uplink A has a good RSSI but a low SNR, uplink B the other way around
+	upA, upB := newReferenceUplink(), newReferenceUplink()
+	upA.GatewayMetadata.Rssi, upA.GatewayMetadata.Snr = -20, 4
+	upB.GatewayMetadata.Rssi, upB.GatewayMetadata.Snr = -90, 6
+
+	scores := func(snrDominance float64) (scoreA, scoreB uint32) {
+		r := &router{snrDominance: snrDominance}
+		scoreA = r.buildDownlinkOptions(upA, false, newReferenceGateway(t, "EU_863_870"))[1].Score
+		scoreB = r.buildDownlinkOptions(upB, false, newReferenceGateway(t, "EU_863_870"))[1].Score
+		return
+	}
+
+	// With SNR-dominant weighting, the SNR difference outweighs the RSSI difference
+	scoreA, scoreB := scores(1)
+	a.So(scoreB, ShouldBeLessThan, scoreA)
+
+	// With RSSI-dominant weighting, the RSSI difference outweighs the SNR difference
+	scoreA, scoreB = scores(-1)
+	a.So(scoreA, ShouldBeLessThan, scoreB)
+}
+
+func TestHandleDownlinkAirtimeQuota(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkAirtimeQuota"),
+		},
+		gateways:     map[string]*gateway.Gateway{},
+		airtimeQuota: 100 * time.Millisecond,
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	downlink := func(timestamp uint32) *pb_broker.DownlinkMessage {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Timestamp = timestamp
+		return &pb_broker.DownlinkMessage{
+			Payload:        up.Payload,
+			DownlinkOption: r.buildDownlinkOptions(up, false, gtw)[1],
+		}
+	}
+
+	// Downlinks that can not be scheduled do not use quota
+	for i := 0; i < 3; i++ {
+		cancelled := downlink(uint32(i * 1000000))
+		gtw.Schedule.Cancel(cancelled.DownlinkOption.Identifier)
+		res, err := r.HandleDownlink(cancelled)
+		a.So(err, ShouldNotBeNil)
+		a.So(res.NackReason, ShouldEqual, NackScheduleConflict)
+	}
+
+	// Each downlink takes about 40ms of airtime
+	_, err := r.HandleDownlink(downlink(0))
+	a.So(err, ShouldBeNil)
+	_, err = r.HandleDownlink(downlink(10000000))
+	a.So(err, ShouldBeNil)
+	res, err := r.HandleDownlink(downlink(20000000))
+	a.So(err, ShouldEqual, ErrQuotaExceeded)
+	a.So(res.NackReason, ShouldEqual, NackQuotaExceeded)
+
+	// Other networks have their own quota
+	other := downlink(30000000)
+	other.Payload[4] = 0x26 // DevAddr 26020304
+	_, err = r.HandleDownlink(other)
+	a.So(err, ShouldBeNil)
+}
+
+func TestHandleDownlinkDwellTime(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkDwellTime"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "AS_923"})
+
+	downlink := func(timestamp uint32, payloadSize int) *pb_broker.DownlinkMessage {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Frequency = 923200000
+		up.GatewayMetadata.Timestamp = timestamp
+		return &pb_broker.DownlinkMessage{
+			Payload:        make([]byte, payloadSize),
+			DownlinkOption: r.buildDownlinkOptions(up, false, gtw)[1],
+		}
+	}
+
+	// Too large for the dwell time of 400ms, even at SF7BW250
+	res, err := r.HandleDownlink(downlink(0, 600))
+	a.So(err, ShouldEqual, ErrDwellTimeExceeded)
+	a.So(res.NackReason, ShouldEqual, NackDwellTime)
+
+	res, err = r.HandleDownlink(downlink(10000000, 20))
+	a.So(err, ShouldBeNil)
+	a.So(res.Accepted, ShouldBeTrue)
+}
+
+func TestHandleDownlinkAlwaysRX2(t *testing.T) {
+	a := New(t)
+
+	for _, alwaysRX2 := range []bool{false, true} {
+		r := &router{
+			Component: &component.Component{
+				Ctx: GetLogger(t, "TestHandleDownlinkAlwaysRX2"),
+			},
+			gateways:  map[string]*gateway.Gateway{},
+			alwaysRX2: alwaysRX2,
+		}
+		r.InitStatus()
+
+		gtw := r.getGateway("eui-0102030405060708")
+		gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+		options := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+		a.So(options, ShouldHaveLength, 2)
+		a.So(options[0].GatewayConfig.Timestamp, ShouldEqual, 2000100)
+
+		res, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+			Payload:        newReferenceDownlink().Payload,
+			DownlinkOption: options[1],
+		})
+		a.So(err, ShouldBeNil)
+		a.So(res.Accepted, ShouldBeTrue)
+
+		// RX1 is always booked, RX2 only if the mode is on
+		a.So(gtw.Schedule.Conflicts(1000100, 1), ShouldBeGreaterThanOrEqualTo, 100)
+		if alwaysRX2 {
+			a.So(gtw.Schedule.Conflicts(2000100, 1), ShouldBeGreaterThanOrEqualTo, 100)
+		} else {
+			a.So(gtw.Schedule.Conflicts(2000100, 1), ShouldBeLessThan, 100)
+		}
+	}
+}
+
+func TestHandleDownlinkAlwaysRX2DutyCycle(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkAlwaysRX2DutyCycle"),
+		},
+		gateways:  map[string]*gateway.Gateway{},
+		alwaysRX2: true,
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+
+	// Exceed the duty cycle on the RX2 channel before the downlink arrives
+	for i := 0; i < 20; i++ {
+		downlink := newReferenceDownlink()
+		downlink.GatewayConfiguration.Frequency = 869525000
+		gtw.Utilization.AddTx(downlink)
+	}
+	gtw.Utilization.Tick()
+
+	res, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        newReferenceDownlink().Payload,
+		DownlinkOption: options[1],
+	})
+	a.So(err, ShouldBeNil)
+	a.So(res.Accepted, ShouldBeTrue)
+
+	// The downlink is sent in RX1, but the copy in RX2 is dropped
+	a.So(gtw.Schedule.Conflicts(1000100, 1), ShouldBeGreaterThanOrEqualTo, 100)
+	a.So(gtw.Schedule.Conflicts(2000100, 1), ShouldBeLessThan, 100)
+}
+
+func TestHandleDownlinkAttemptsTxAck(t *testing.T) {
+	a := New(t)
+
+	for _, txErr := range []error{nil, &pb.TxError{Reason: pb.TxAcknowledgment_TOO_LATE}} {
+		r := &router{
+			Component: &component.Component{
+				Ctx: GetLogger(t, "TestHandleDownlinkAttemptsTxAck"),
+			},
+			gateways:         map[string]*gateway.Gateway{},
+			downlinkAttempts: true,
+		}
+		r.InitStatus()
+
+		gtw := r.getGateway("eui-0102030405060708")
+		gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+		options := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+		a.So(options, ShouldHaveLength, 2)
+		rx1 := options[1]
+
+		res, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+			Payload:        newReferenceDownlink().Payload,
+			DownlinkOption: rx1,
+		})
+		a.So(err, ShouldBeNil)
+		a.So(res.Accepted, ShouldBeTrue)
+
+		// RX1 and RX2 are both booked
+		a.So(gtw.Schedule.Conflicts(1000100, 1), ShouldBeGreaterThanOrEqualTo, 100)
+		a.So(gtw.Schedule.Conflicts(2000100, 1), ShouldBeGreaterThanOrEqualTo, 100)
+
+		// RX1 is charged when it is sent to the gateway
+		_, sent := r.gatewayDownlink(&pb_broker.DownlinkMessage{
+			Payload:        newReferenceDownlink().Payload,
+			DownlinkOption: rx1,
+		})
+		gtw.HandleSent(sent)
+
+		a.So(r.HandleTxAck(gtw.ID, rx1.Identifier, txErr), ShouldBeNil)
+		gtw.Utilization.Tick()
+		_, rx1Tx := gtw.Utilization.GetChannel(rx1.GatewayConfig.Frequency)
+		if txErr == nil {
+			// RX2 is cancelled and only RX1 charges airtime
+			a.So(gtw.Schedule.Conflicts(2000100, 1), ShouldBeLessThan, 100)
+			a.So(rx1Tx, ShouldBeGreaterThan, 0)
+		} else {
+			// RX2 is still sent if RX1 was not, and RX1 is refunded
+			a.So(gtw.Schedule.Conflicts(2000100, 1), ShouldBeGreaterThanOrEqualTo, 100)
+			a.So(rx1Tx, ShouldEqual, 0)
+		}
+	}
+}
+
+// spanAttributes returns the attributes of the span by key
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestHandleDownlinkSpan(t *testing.T) {
+	a := New(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	r := &router{
+		Component: &component.Component{
+			Ctx:            GetLogger(t, "TestHandleDownlinkSpan"),
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	up := newReferenceUplink()
+	options := r.buildDownlinkOptions(up, false, gtw)
+	a.So(options, ShouldHaveLength, 2)
+	rx1 := options[1]
+
+	_, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        make([]byte, 20),
+		DownlinkOption: rx1,
+	})
+	a.So(err, ShouldBeNil)
+
+	spans := exporter.GetSpans()
+	a.So(spans, ShouldHaveLength, 1)
+	a.So(spans[0].Name, ShouldEqual, "router.HandleDownlink")
+	attributes := spanAttributes(spans[0])
+	a.So(attributes["gateway_id"].AsString(), ShouldEqual, "eui-0102030405060708")
+	a.So(attributes["window"].AsString(), ShouldEqual, "RX1")
+	a.So(attributes["score"].AsInt64(), ShouldEqual, int64(rx1.Score))
+	a.So(attributes["drops"].AsInt64(), ShouldEqual, int64(0))
+	a.So(attributes["accepted"].AsBool(), ShouldBeTrue)
+
+	up.GatewayMetadata.Timestamp = 10000000
+	options = r.buildDownlinkOptions(up, false, gtw)
+	rx2 := options[0]
+
+	_, err = r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        make([]byte, 20),
+		DownlinkOption: rx2,
+	})
+	a.So(err, ShouldBeNil)
+
+	spans = exporter.GetSpans()
+	a.So(spans, ShouldHaveLength, 2)
+	attributes = spanAttributes(spans[1])
+	a.So(attributes["window"].AsString(), ShouldEqual, "RX2")
+	a.So(attributes["score"].AsInt64(), ShouldEqual, int64(rx2.Score))
+
+	// A downlink that can not be scheduled is traced with its nack reason
+	_, err = r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        make([]byte, 20),
+		DownlinkOption: rx2,
+	})
+	a.So(err, ShouldNotBeNil)
+
+	spans = exporter.GetSpans()
+	a.So(spans, ShouldHaveLength, 3)
+	attributes = spanAttributes(spans[2])
+	a.So(attributes["accepted"].AsBool(), ShouldBeFalse)
+	a.So(attributes["nack_reason"].AsString(), ShouldEqual, string(NackScheduleConflict))
+
+	// Without a TracerProvider, no spans are emitted, but the window is still
+	// known for the result
+	r.Component.TracerProvider = nil
+	up.GatewayMetadata.Timestamp = 20000000
+	options = r.buildDownlinkOptions(up, false, gtw)
+	res, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        make([]byte, 20),
+		DownlinkOption: options[1],
+	})
+	a.So(err, ShouldBeNil)
+	a.So(res.Window, ShouldEqual, WindowRX1)
+	a.So(exporter.GetSpans(), ShouldHaveLength, 3)
+	_, ok := r.optionTraces.get(options[1].Identifier)
+	a.So(ok, ShouldBeFalse)
+}
+
+func TestHandleDownlinkAttemptsSpan(t *testing.T) {
+	a := New(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	r := &router{
+		Component: &component.Component{
+			Ctx:            GetLogger(t, "TestHandleDownlinkAttemptsSpan"),
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+	err := r.HandleDownlinkAttempts(
+		&pb_broker.DownlinkMessage{Payload: make([]byte, 20), DownlinkOption: options[1]},
+		&pb_broker.DownlinkMessage{Payload: make([]byte, 20), DownlinkOption: options[0]},
+	)
+	a.So(err, ShouldBeNil)
+
+	spans := exporter.GetSpans()
+	a.So(spans, ShouldHaveLength, 2)
+	a.So(spans[0].Name, ShouldEqual, "gateway.HandleDownlinkAttempts")
+	a.So(spans[1].Name, ShouldEqual, "router.HandleDownlinkAttempts")
+	attributes := spanAttributes(spans[1])
+	a.So(attributes["gateway_id"].AsString(), ShouldEqual, gtw.ID)
+	a.So(attributes["attempts"].AsInt64(), ShouldEqual, 2)
+
+	// The TxAck of the first attempt cancels the second one
+	a.So(r.HandleTxAck(gtw.ID, options[1].Identifier, nil), ShouldBeNil)
+	spans = exporter.GetSpans()
+	a.So(spans, ShouldHaveLength, 3)
+	a.So(spans[2].Name, ShouldEqual, "gateway.HandleTxAck")
+	attributes = spanAttributes(spans[2])
+	a.So(attributes["attempt"].AsBool(), ShouldBeTrue)
+	a.So(attributes["transmitted"].AsBool(), ShouldBeTrue)
+	a.So(attributes["cancelled"].AsInt64(), ShouldEqual, 1)
+}
+
+func TestHandleTxAckPowerError(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleTxAckPowerError"),
+		},
+		gateways:            map[string]*gateway.Gateway{},
+		powerErrorThreshold: 3,
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	options := func(timestamp uint32) []*pb_broker.DownlinkOption {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Timestamp = timestamp
+		return r.buildDownlinkOptions(up, false, gtw)
+	}
+
+	sendRX1 := func(timestamp uint32, err error) {
+		option := options(timestamp)[1]
+		a.So(option.GatewayConfig.Power, ShouldEqual, 14)
+		res, _ := r.HandleDownlink(&pb_broker.DownlinkMessage{
+			Payload:        newReferenceDownlink().Payload,
+			DownlinkOption: option,
+		})
+		a.So(res.Accepted, ShouldBeTrue)
+		r.HandleTxAck(gtw.ID, option.Identifier, err)
+	}
+
+	powerError := (&pb.TxAcknowledgment{Error: pb.TxAcknowledgment_TX_POWER}).Err()
+
+	// Other errors and fewer power errors than the threshold do not lower the power
+	sendRX1(0, (&pb.TxAcknowledgment{Error: pb.TxAcknowledgment_TOO_LATE}).Err())
+	sendRX1(5000000, errors.New("TX_POWER"))
+	sendRX1(10000000, powerError)
+	sendRX1(20000000, powerError)
+
+	opts := options(30000000)
+	a.So(opts[1].GatewayConfig.Power, ShouldEqual, 14)
+	a.So(opts[0].GatewayConfig.Power, ShouldEqual, 27)
+
+	sendRX1(40000000, powerError)
+
+	max, ok := gtw.MaxTXPower()
+	a.So(ok, ShouldBeTrue)
+	a.So(max, ShouldEqual, 13)
+
+	opts = options(50000000)
+	a.So(opts[1].GatewayConfig.Power, ShouldEqual, 13)
+	a.So(opts[0].GatewayConfig.Power, ShouldEqual, 13)
+
+	// A maximum below the minimum TX power of the gateway is not overridden,
+	// the gateway is not used for downlink instead
+	gtw.MinTXPower = 14
+	a.So(options(60000000), ShouldBeEmpty)
+	gtw.MinTXPower = 0
+
+	// The maximum expires after PowerCapRetention
+	defer func(retention time.Duration) { gateway.PowerCapRetention = retention }(gateway.PowerCapRetention)
+	gateway.PowerCapRetention = 0
+	_, ok = gtw.MaxTXPower()
+	a.So(ok, ShouldBeFalse)
+
+	opts = options(70000000)
+	a.So(opts[1].GatewayConfig.Power, ShouldEqual, 14)
+	a.So(opts[0].GatewayConfig.Power, ShouldEqual, 27)
+}
+
+func TestHandleDownlinkFPort(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkFPort"),
+		},
+		gateways:     map[string]*gateway.Gateway{},
+		airtimeQuota: 100 * time.Millisecond,
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	downlink := func(timestamp uint32, fPort uint8) *pb_broker.DownlinkMessage {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Timestamp = timestamp
+		phy := lorawan.PHYPayload{
+			MHDR: lorawan.MHDR{
+				MType: lorawan.UnconfirmedDataDown,
+				Major: lorawan.LoRaWANR1,
+			},
+			MACPayload: &lorawan.MACPayload{
+				FHDR: lorawan.FHDR{
+					DevAddr: lorawan.DevAddr([4]byte{1, 2, 3, 4}),
+				},
+				FPort: &fPort,
+			},
+		}
+		bytes, _ := phy.MarshalBinary()
+		return &pb_broker.DownlinkMessage{
+			Payload:        bytes,
+			DownlinkOption: r.buildDownlinkOptions(up, false, gtw)[1],
+		}
+	}
+
+	res, err := r.HandleDownlink(downlink(0, 1))
+	a.So(err, ShouldBeNil)
+	a.So(res.FPort, ShouldEqual, 1)
+	a.So(res.MAC, ShouldBeFalse)
+	_, err = r.HandleDownlink(downlink(10000000, 1))
+	a.So(err, ShouldBeNil)
+	_, err = r.HandleDownlink(downlink(20000000, 1))
+	a.So(err, ShouldEqual, ErrQuotaExceeded)
+
+	// MAC-only downlinks are still sent when the quota is exceeded
+	res, err = r.HandleDownlink(downlink(30000000, 0))
+	a.So(err, ShouldBeNil)
+	a.So(res.Accepted, ShouldBeTrue)
+	a.So(res.FPort, ShouldEqual, 0)
+	a.So(res.MAC, ShouldBeTrue)
+}
+
+func TestHandleDownlinkClassQuota(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkClassQuota"),
+		},
+		gateways:    map[string]*gateway.Gateway{},
+		classQuotas: map[string]float64{"bulk": 0.1 / 3600}, // 100ms per hour
+		classFPorts: map[string][]int{"bulk": {200}},
+	}
+	r.InitStatus()
+
+	a.So(r.priorityClass(0, true), ShouldEqual, ClassSystem)
+	a.So(r.priorityClass(1, true), ShouldEqual, ClassRealtime)
+	a.So(r.priorityClass(200, true), ShouldEqual, "bulk")
+	a.So(r.priorityClass(0, false), ShouldEqual, ClassRealtime)
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	downlink := func(timestamp uint32, fPort uint8) *pb_broker.DownlinkMessage {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Timestamp = timestamp
+		phy := lorawan.PHYPayload{
+			MHDR: lorawan.MHDR{
+				MType: lorawan.UnconfirmedDataDown,
+				Major: lorawan.LoRaWANR1,
+			},
+			MACPayload: &lorawan.MACPayload{
+				FHDR: lorawan.FHDR{
+					DevAddr: lorawan.DevAddr([4]byte{1, 2, 3, 4}),
+				},
+				FPort: &fPort,
+			},
+		}
+		bytes, _ := phy.MarshalBinary()
+		return &pb_broker.DownlinkMessage{
+			Payload:        bytes,
+			DownlinkOption: r.buildDownlinkOptions(up, false, gtw)[1],
+		}
+	}
+
+	// Downlinks that can not be scheduled do not use quota
+	for i := 0; i < 3; i++ {
+		cancelled := downlink(uint32(i*1000000), 200)
+		gtw.Schedule.Cancel(cancelled.DownlinkOption.Identifier)
+		res, err := r.HandleDownlink(cancelled)
+		a.So(err, ShouldNotBeNil)
+		a.So(res.NackReason, ShouldEqual, NackScheduleConflict)
+	}
+
+	// Each downlink takes about 40ms of airtime
+	_, err := r.HandleDownlink(downlink(0, 200))
+	a.So(err, ShouldBeNil)
+	_, err = r.HandleDownlink(downlink(10000000, 200))
+	a.So(err, ShouldBeNil)
+	res, err := r.HandleDownlink(downlink(20000000, 200))
+	a.So(err, ShouldEqual, gateway.ErrClassQuotaExceeded)
+	a.So(res.NackReason, ShouldEqual, NackClassQuotaExceeded)
+
+	// Realtime downlinks are not affected by the quota of bulk downlinks
+	res, err = r.HandleDownlink(downlink(30000000, 1))
+	a.So(err, ShouldBeNil)
+	a.So(res.Accepted, ShouldBeTrue)
+}
+
+func TestUplinkBuildDownlinkOptionsForbiddenRX1(t *testing.T) {
+	a := New(t)
+
+	var reasons []interface{}
+	logger := &log.Logger{
+		Level: log.DebugLevel,
+		Handler: log.HandlerFunc(func(entry *log.Entry) error {
+			if reason, ok := entry.Fields["Reason"]; ok {
+				reasons = append(reasons, reason)
+			}
+			return nil
+		}),
+	}
+
+	r := &router{
+		forbiddenFrequencies: map[string][]int{
+			"EU_863_870": {868300000},
+			"US_902_928": {923900000},
+		},
+	}
+
+	for _, tt := range []struct {
+		region          string
+		uplinkFrequency uint64
+		rx1Frequency    uint64
+		reason          string
+	}{
+		{"EU_863_870", 869300000, 869300000, forbiddenOutsideSubBands}, // European Alarm Band
+		{"EU_863_870", 868300000, 868300000, forbiddenByConfiguration},
+		{"US_902_928", 904100000, 923900000, forbiddenByConfiguration},
+	} {
+		reasons = nil
+		gtw := gateway.NewGateway(logger, "eui-0102030405060708")
+		gtw.Status.Update(&pb_gateway.Status{Region: tt.region})
+		up := newReferenceUplink()
+		up.GatewayMetadata.Frequency = tt.uplinkFrequency
+		options := r.buildDownlinkOptions(up, false, gtw)
+		a.So(options, ShouldHaveLength, 1) // RX1 Removed
+		a.So(options[0].GatewayConfig.Frequency, ShouldNotEqual, tt.rx1Frequency)
+		a.So(reasons, ShouldResemble, []interface{}{tt.reason})
+
+		// The reason is traced with the RX2 option that remains
+		trace, ok := r.optionTraces.get(options[0].Identifier)
+		a.So(ok, ShouldBeTrue)
+		a.So(trace.drops, ShouldResemble, []string{"RX1 frequency not valid: " + tt.reason})
+	}
+
+	// RX1 on other frequencies is not affected
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 2)
+}
+
+func TestUplinkBuildDownlinkOptionsMaxRX1DataRate(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+
+	gtw := newReferenceGateway(t, "EU_863_870")
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+	a.So(options[1].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF7BW125")
+
+	// RX1 is clamped to the maximum data rate of the gateway
+	gtw = newReferenceGateway(t, "EU_863_870")
+	gtw.MaxRX1DataRate, _ = types.ParseDataRate("SF9BW125")
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+	a.So(options[1].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF9BW125")
+
+	// Slower uplink data rates are not affected
+	up := newReferenceUplink()
+	up.ProtocolMetadata.GetLorawan().DataRate = "SF10BW125"
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options[1].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF10BW125")
+
+	// RX1 is only clamped to the data rates that are legal in RX1 for the
+	// uplink data rate, which is DR10 for DR3 in US_902_928
+	gtw = newReferenceGateway(t, "US_902_928")
+	gtw.MaxRX1DataRate, _ = types.ParseDataRate("SF12BW500")
+	up = newReferenceUplink()
+	up.GatewayMetadata.Frequency = 903900000
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options[1].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF10BW500")
+}
+
+func TestHandleDownlinkTraceID(t *testing.T) {
+	a := New(t)
+
+	var traceIDsMu sync.Mutex
+	var traceIDs []interface{}
+	logger := &log.Logger{
+		Level: log.DebugLevel,
+		Handler: log.HandlerFunc(func(entry *log.Entry) error {
+			traceIDsMu.Lock()
+			defer traceIDsMu.Unlock()
+			if traceID, ok := entry.Fields["TraceID"]; ok {
+				traceIDs = append(traceIDs, traceID)
+			}
+			return nil
+		}),
+	}
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: logger,
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtwID := "eui-0102030405060708"
+	gateway.Deadline = 1 * time.Millisecond
+	gtw := r.getGateway(gtwID)
+	gtw.Schedule.Sync(0)
+	id, _ := gtw.Schedule.GetOption(5000, 10*1000)
+
+	ch, err := r.SubscribeDownlink(gtwID, "")
+	a.So(err, ShouldBeNil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		for dl := range ch {
+			a.So(dl.TraceId, ShouldEqual, "trace-1")
+		}
+		wg.Done()
+	}()
+
+	_, err = r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload: []byte{0x02},
+		DownlinkOption: &pb_broker.DownlinkOption{
+			GatewayId:      gtwID,
+			Identifier:     id,
+			ProtocolConfig: &pb_protocol.TxConfiguration{},
+			GatewayConfig:  &pb_gateway.TxConfiguration{},
+		},
+		TraceId: "trace-1",
+	})
+	a.So(err, ShouldBeNil)
+
+	// Wait for the downlink to arrive
+	<-time.After(10 * time.Millisecond)
+
+	err = r.UnsubscribeDownlink(gtwID, "")
+	a.So(err, ShouldBeNil)
+
+	wg.Wait()
+
+	traceIDsMu.Lock()
+	defer traceIDsMu.Unlock()
+	a.So(traceIDs, ShouldContain, "trace-1")
+	a.So(len(traceIDs), ShouldBeGreaterThanOrEqualTo, 2) // Scheduled and sent
+}
+
+func TestHandleDownlinkTraceFrames(t *testing.T) {
+	a := New(t)
+
+	sendDownlink := func(traceFrames bool) (frames []interface{}) {
+		var framesMu sync.Mutex
+		logger := &log.Logger{
+			Level: log.DebugLevel,
+			Handler: log.HandlerFunc(func(entry *log.Entry) error {
+				framesMu.Lock()
+				defer framesMu.Unlock()
+				if frame, ok := entry.Fields["Frame"]; ok {
+					frames = append(frames, frame)
+				}
+				return nil
+			}),
+		}
+
+		r := &router{
+			Component: &component.Component{
+				Ctx: logger,
+			},
+			gateways:    map[string]*gateway.Gateway{},
+			traceFrames: traceFrames,
+		}
+		r.InitStatus()
+
+		gtwID := "eui-0102030405060708"
+		gateway.Deadline = 1 * time.Millisecond
+		gtw := r.getGateway(gtwID)
+		gtw.Schedule.Sync(0)
+		id, _ := gtw.Schedule.GetOption(5000, 10*1000)
+
+		ch, err := r.SubscribeDownlink(gtwID, "")
+		a.So(err, ShouldBeNil)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			for range ch {
+			}
+			wg.Done()
+		}()
+
+		_, err = r.HandleDownlink(&pb_broker.DownlinkMessage{
+			Payload: []byte{0x60, 0x04, 0x03, 0x02, 0x01},
+			DownlinkOption: &pb_broker.DownlinkOption{
+				GatewayId:      gtwID,
+				Identifier:     id,
+				ProtocolConfig: &pb_protocol.TxConfiguration{},
+				GatewayConfig:  &pb_gateway.TxConfiguration{},
+			},
+		})
+		a.So(err, ShouldBeNil)
+
+		// Wait for the downlink to arrive
+		<-time.After(10 * time.Millisecond)
+
+		r.UnsubscribeDownlink(gtwID, "")
+		wg.Wait()
+
+		framesMu.Lock()
+		defer framesMu.Unlock()
+		return frames
+	}
+
+	a.So(sendDownlink(false), ShouldBeEmpty)
+	a.So(sendDownlink(true), ShouldResemble, []interface{}{"6004030201"})
+}
+
+func TestHandleDownlinkResult(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkResult"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	// Accepted downlink contains the window
+	option := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)[1]
+	res, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        newReferenceUplink().Payload,
+		DownlinkOption: option,
+	})
+	a.So(err, ShouldBeNil)
+	a.So(res.Accepted, ShouldBeTrue)
+	a.So(res.Window, ShouldEqual, WindowRX1)
+	a.So(res.Timestamp, ShouldEqual, option.GatewayConfig.Timestamp)
+	a.So(res.Frequency, ShouldEqual, 868100000)
+
+	// The result is sent to the broker with the identifiers of the downlink
+	brokerResult := res.brokerResult(&pb_broker.DownlinkMessage{AppId: "app", DevId: "dev", TraceId: "trace"})
+	a.So(brokerResult.AppId, ShouldEqual, "app")
+	a.So(brokerResult.DevId, ShouldEqual, "dev")
+	a.So(brokerResult.TraceId, ShouldEqual, "trace")
+	a.So(brokerResult.Accepted, ShouldBeTrue)
+	a.So(brokerResult.GatewayId, ShouldEqual, gtw.ID)
+	a.So(brokerResult.Window, ShouldEqual, WindowRX1)
+	a.So(brokerResult.Frequency, ShouldEqual, 868100000)
+
+	// The RX2 option is sent in RX2
+	up := newReferenceUplink()
+	up.GatewayMetadata.Timestamp = 5000000
+	option = r.buildDownlinkOptions(up, false, gtw)[0]
+	res, err = r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        up.Payload,
+		DownlinkOption: option,
+	})
+	a.So(err, ShouldBeNil)
+	a.So(res.Window, ShouldEqual, WindowRX2)
+
+	// Exceed the duty cycle on the RX1 channel
+	up = newReferenceUplink()
+	up.GatewayMetadata.Timestamp = 10000000
+	option = r.buildDownlinkOptions(up, false, gtw)[1]
+	for i := 0; i < 5; i++ {
+		gtw.Utilization.AddTx(newReferenceDownlink())
+	}
+	gtw.Utilization.Tick()
+
+	res, err = r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        up.Payload,
+		DownlinkOption: option,
+	})
+	a.So(err, ShouldNotBeNil)
+	a.So(res.Accepted, ShouldBeFalse)
+	a.So(res.NackReason, ShouldEqual, NackDutyCycle)
+
+	// Invalid downlink
+	res, err = r.HandleDownlink(&pb_broker.DownlinkMessage{})
+	a.So(err, ShouldNotBeNil)
+	a.So(res.NackReason, ShouldEqual, NackInvalid)
+}
+
+func TestHandleDownlinkRescore(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkRescore"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+		rescore:  true,
+	}
+	r.InitStatus()
+
+	gtwA := r.getGateway("eui-0102030405060708")
+	gtwA.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+	gtwB := r.getGateway("eui-0807060504030201")
+	gtwB.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	// Both gateways receive the uplink, but only gateway A is connected for downlink
+	gtwA.Schedule.Subscribe("a")
+	upA, upB := newReferenceUplink(), newReferenceUplink()
+	upA.GatewayMetadata.Rssi = -100
+	devAddr, _ := devAddrFromPayload(upA.Payload)
+	r.recentUplinks.add(devAddr, gtwA.ID, upA)
+	r.recentUplinks.add(devAddr, gtwB.ID, upB)
+	option := r.buildDownlinkOptions(upA, false, gtwA)[1]
+
+	// The superior gateway B connects before the downlink is handled
+	gtwB.Schedule.Subscribe("b")
+
+	res, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        upA.Payload,
+		DownlinkOption: option,
+	})
+	a.So(err, ShouldBeNil)
+	a.So(res.Accepted, ShouldBeTrue)
+	a.So(res.GatewayID, ShouldEqual, gtwB.ID)
+	a.So(res.Frequency, ShouldEqual, option.GatewayConfig.Frequency)
+
+	// Only the option that is used is booked while re-scoring
+	a.So(gtwA.Schedule.Conflicts(option.GatewayConfig.Timestamp, 1000), ShouldEqual, 1)
+	a.So(gtwB.Schedule.Conflicts(upB.GatewayMetadata.Timestamp+2000000, 1000), ShouldEqual, 0)
+
+	// Without re-scoring, the option of gateway A is used
+	r.rescore = false
+	upA.GatewayMetadata.Timestamp = 10000000
+	option = r.buildDownlinkOptions(upA, false, gtwA)[1]
+	res, err = r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        upA.Payload,
+		DownlinkOption: option,
+	})
+	a.So(err, ShouldBeNil)
+	a.So(res.GatewayID, ShouldEqual, gtwA.ID)
+}
+
+func TestHandleDownlinkSafeMode(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestHandleDownlinkSafeMode"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	downlink := func(timestamp uint32) *pb_broker.DownlinkMessage {
+		up := newReferenceUplink()
+		up.GatewayMetadata.Timestamp = timestamp
+		return &pb_broker.DownlinkMessage{
+			Payload:        up.Payload,
+			DownlinkOption: r.buildDownlinkOptions(up, false, gtw)[1],
+		}
+	}
+
+	// Downlinks are rejected while the safe mode is active
+	r.SetDownlinksDisabled(true)
+	res, err := r.HandleDownlink(downlink(0))
+	a.So(err, ShouldEqual, ErrDownlinksDisabled)
+	a.So(res.Accepted, ShouldBeFalse)
+	a.So(res.NackReason, ShouldEqual, NackDownlinksDisabled)
+	a.So(r.HandleDownlinkAttempts(downlink(10000000)), ShouldEqual, ErrDownlinksDisabled)
+
+	// Downlinks are accepted again after the safe mode is deactivated
+	r.SetDownlinksDisabled(false)
+	res, err = r.HandleDownlink(downlink(20000000))
+	a.So(err, ShouldBeNil)
+	a.So(res.Accepted, ShouldBeTrue)
+}
+
+func TestDutyCycleGroups(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestDutyCycleGroups"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+		dutyCycleGroupConfig: map[string]string{
+			"eui-0102030405060708": "site-1",
+			"eui-0807060504030201": "site-1",
+		},
+	}
+
+	gtwA := r.getGateway("eui-0102030405060708")
+	gtwB := r.getGateway("eui-0807060504030201")
+	gtwC := r.getGateway("eui-0101010101010101")
+	for _, gtw := range []*gateway.Gateway{gtwA, gtwB, gtwC} {
+		gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+	}
+
+	// Gateway A exceeds the duty cycle on the RX1 channel
+	for i := 0; i < 5; i++ {
+		gtwA.Utilization.AddTx(newReferenceDownlink())
+	}
+	gtwA.Utilization.Tick()
+
+	plan, _ := r.getFrequencyPlan("EU_863_870")
+	a.So(isSaturated(gtwB, plan, 868100000), ShouldBeTrue)
+	a.So(isSaturated(gtwC, plan, 868100000), ShouldBeFalse)
+
+	// Gateway B can only use RX2
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, gtwB)
+	a.So(options, ShouldHaveLength, 1)
+	a.So(options[0].GatewayConfig.Frequency, ShouldEqual, 869525000)
+}
+
+func TestDutyCycleOverride(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestDutyCycleOverride"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+		gatewayAttributes: map[string]gateway.Attributes{
+			"eui-0102030405060708": {DutyCycle: map[string]float64{"g1": 0.05}},
+		},
+	}
+	plan, _ := r.getFrequencyPlan("EU_863_870")
+
+	busyGateway := func(id string, downlinks int) *gateway.Gateway {
+		r.gateways = map[string]*gateway.Gateway{}
+		gtw := r.getGateway(id)
+		gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+		for i := 0; i < downlinks; i++ {
+			gtw.Utilization.AddTx(newReferenceDownlink())
+		}
+		gtw.Utilization.Tick()
+		return gtw
+	}
+
+	// About 3% of the time on the RX1 channel exceeds the 1% default
+	a.So(isSaturated(busyGateway("eui-0807060504030201", 3), plan, 868100000), ShouldBeTrue)
+
+	// But not the 5% override of the g1 sub-band
+	gtw := busyGateway("eui-0102030405060708", 3)
+	a.So(gtw.DutyCycleOverrides["g1"], ShouldEqual, 0.05)
+	a.So(isSaturated(gtw, plan, 868100000), ShouldBeFalse)
+	a.So(r.buildDownlinkOptions(newReferenceUplink(), false, gtw), ShouldHaveLength, 2)
+
+	// Other sub-bands keep their limit
+	duty, _ := getGatewayDutyCycle(gtw, "EU_863_870", 869525000)
+	a.So(duty, ShouldEqual, 0.1)
+
+	// The gateway is throttled when it exceeds the override
+	a.So(isSaturated(busyGateway("eui-0102030405060708", 10), plan, 868100000), ShouldBeTrue)
+
+	// Overrides of unknown sub-bands are invalid
+	a.So(validateDutyCycleOverrides(r.gatewayAttributes), ShouldBeNil)
+	a.So(validateDutyCycleOverrides(map[string]gateway.Attributes{
+		"eui-0102030405060708": {DutyCycle: map[string]float64{"g5": 0.05}},
+	}), ShouldNotBeNil)
+}
+
+func TestUplinkBuildDownlinkOptionsRX1WindowTolerance(t *testing.T) {
+	a := New(t)
+
+	// The RX1 window tolerance of EU_863_870 is 5ms
+	r := &router{}
+	refScore := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))[1].Score
+
+	// A conflict of 5ms at the start of RX1 is resolved by shifting within the tolerance
+	gtw := newReferenceGateway(t, "EU_863_870")
+	gtw.Schedule.GetOption(900100, 105000)
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+	a.So(options[1].GatewayConfig.Timestamp, ShouldEqual, 1005100)
+	a.So(options[1].Score, ShouldEqual, refScore)
+
+	// A conflict of 6ms can not be resolved within the tolerance
+	gtw = newReferenceGateway(t, "EU_863_870")
+	gtw.Schedule.GetOption(900100, 106000)
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+	a.So(options[1].GatewayConfig.Timestamp, ShouldEqual, 1000100)
+	a.So(options[1].Score, ShouldBeGreaterThan, refScore)
+
+	// Without tolerance, downlinks are not shifted
+	plan, _ := band.Get("EU_863_870")
+	plan.RX1WindowTolerance = 0
+	r = &router{
+		frequencyPlans: map[string]band.FrequencyPlan{"EU_863_870": plan},
+	}
+	gtw = newReferenceGateway(t, "EU_863_870")
+	gtw.Schedule.GetOption(900100, 105000)
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, gtw)
+	a.So(options[1].GatewayConfig.Timestamp, ShouldEqual, 1000100)
+}
+
+func TestUplinkBuildDownlinkOptionsFSKRX2(t *testing.T) {
+	a := New(t)
+
+	plan, _ := band.Get("EU_863_870")
+	rx2DataRate := 7 // FSK 50kbps
+	override := &band.FrequencyPlanOverride{RX2DataRate: &rx2DataRate}
+	a.So(override.Apply(&plan), ShouldBeNil)
+	r := &router{
+		frequencyPlans: map[string]band.FrequencyPlan{"EU_863_870": plan},
+	}
+
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 2)
+
+	// RX2 uses FSK
+	rx2 := options[0]
+	a.So(rx2.ProtocolConfig.GetLorawan().Modulation, ShouldEqual, pb_lorawan.Modulation_FSK)
+	a.So(rx2.ProtocolConfig.GetLorawan().BitRate, ShouldEqual, 50000)
+	a.So(rx2.ProtocolConfig.GetLorawan().DataRate, ShouldBeEmpty)
+	a.So(rx2.GatewayConfig.FrequencyDeviation, ShouldEqual, 25000)
+	a.So(rx2.GatewayConfig.PolarizationInversion, ShouldBeFalse)
+
+	// RX1 still uses LoRa
+	rx1 := options[1]
+	a.So(rx1.ProtocolConfig.GetLorawan().Modulation, ShouldEqual, pb_lorawan.Modulation_LORA)
+	a.So(rx1.ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF7BW125")
+	a.So(rx1.GatewayConfig.FrequencyDeviation, ShouldEqual, 0)
+	a.So(rx1.GatewayConfig.PolarizationInversion, ShouldBeTrue)
+}
+
+func TestUplinkBuildDownlinkOptionsSwitchGuard(t *testing.T) {
+	a := New(t)
+
+	plan, _ := band.Get("EU_863_870")
+	plan.ReceiveDelay1 = 100 * time.Microsecond
+	plan.RX1WindowTolerance = 200 * time.Microsecond
+	r := &router{
+		frequencyPlans: map[string]band.FrequencyPlan{"EU_863_870": plan},
+	}
+
+	// Without switch guard, RX1 is allowed
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 2)
+	a.So(options[1].GatewayConfig.Timestamp, ShouldEqual, 200)
+
+	// RX1 starts within the switch guard, but is shifted within the tolerance
+	r.switchGuard = 300 * time.Microsecond
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 2)
+	a.So(options[1].GatewayConfig.Timestamp, ShouldEqual, 400)
+
+	// RX1 starts within the switch guard, beyond the tolerance
+	r.switchGuard = 500 * time.Microsecond
+	options = r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 1)
+	a.So(options[0].GatewayConfig.Frequency, ShouldEqual, 869525000)
+}
+
+func TestUplinkBuildDownlinkOptionsRX1DROffset(t *testing.T) {
+	a := New(t)
+
+	// An offset that is too large is clamped to the lowest RX1 data rate
+	r := &router{rx1DROffsets: map[string][]int{"EU_863_870": {10}}}
+	options := r.buildDownlinkOptions(newReferenceUplink(), false, newReferenceGateway(t, "EU_863_870"))
+	a.So(options, ShouldHaveLength, 2)
+	a.So(options[1].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF12BW125")
+}
+
+func TestSubscriptions(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestSubscriptions"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	a.So(r.Subscriptions(), ShouldBeEmpty)
+
+	r.SubscribeDownlink("eui-0102030405060708", "sub-1")
+	r.SubscribeDownlink("eui-0102030405060708", "sub-2")
+	r.SubscribeDownlink("eui-0807060504030201", "sub-3")
+	r.getGateway("eui-0101010101010101") // Gateway without subscriptions
+
+	a.So(r.Subscriptions(), ShouldResemble, map[string]int{
+		"eui-0102030405060708": 2,
+		"eui-0807060504030201": 1,
+	})
+
+	r.UnsubscribeDownlink("eui-0102030405060708", "sub-1")
+	a.So(r.Subscriptions()["eui-0102030405060708"], ShouldEqual, 1)
+}
+
+// newBenchmarkGateways returns gateways with the given number of occupied slots
+// in their schedules
+func newBenchmarkGateways(numGateways int, occupancy int) []*gateway.Gateway {
+	ctx := &log.Logger{Handler: log.HandlerFunc(func(*log.Entry) error { return nil })}
+
+	gateways := make([]*gateway.Gateway, numGateways)
+	for i := range gateways {
+		gtw := gateway.NewGateway(ctx, fmt.Sprintf("eui-%016x", i))
+		gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+		gtw.Schedule.Sync(0)
+		// Spread the occupied slots around the RX1 and RX2 windows
+		for j := 0; j < occupancy; j++ {
+			gtw.Schedule.GetOption(uint32(j*50000), 50000)
+		}
+		gateways[i] = gtw
+	}
+	return gateways
+}
+
+func benchmarkBuildDownlinkOptions(b *testing.B, numGateways int, occupancy int) {
+	r := &router{}
+	gateways := newBenchmarkGateways(numGateways, occupancy)
+	uplink := newReferenceUplink()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var identifiers [][]string
+		for _, gtw := range gateways {
+			var ids []string
+			for _, option := range r.buildDownlinkOptions(uplink, false, gtw) {
+				ids = append(ids, option.Identifier)
+			}
+			identifiers = append(identifiers, ids)
+		}
+
+		// Remove the options from the schedules, so that the occupancy stays the same
+		b.StopTimer()
+		for i, gtw := range gateways {
+			for _, id := range identifiers[i] {
+				gtw.Schedule.Cancel(id)
+			}
+		}
+		b.StartTimer()
+	}
+}
+
+// BenchmarkBuildDownlinkOptions measures the cost of building and scoring the
+// downlink options of all gateways that received an uplink. Building the
+// options for 100 gateways should stay below one millisecond.
+func BenchmarkBuildDownlinkOptions(b *testing.B) {
+	for _, numGateways := range []int{1, 10, 100} {
+		for _, occupancy := range []int{0, 10, 100} {
+			b.Run(fmt.Sprintf("Gateways=%d/Occupancy=%d", numGateways, occupancy), func(b *testing.B) {
+				benchmarkBuildDownlinkOptions(b, numGateways, occupancy)
+			})
+		}
+	}
+}
+
+func benchmarkComputeDownlinkScores(b *testing.B, numGateways int, occupancy int) {
+	r := &router{}
+	gateways := newBenchmarkGateways(numGateways, occupancy)
+	uplink := newReferenceUplink()
+	plan, _ := band.Get("EU_863_870")
+
+	// Build the options once; scoring without booking leaves the schedules as they are
+	options := make([][]*pb_broker.DownlinkOption, len(gateways))
+	for i, gtw := range gateways {
+		_, _, options[i], _ = r.scoreDownlinkOptions(uplink, false, gtw, false)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i, gtw := range gateways {
+			computeDownlinkScores(gtw, uplink, plan, options[i], 0, false)
+		}
+	}
+}
+
+// BenchmarkComputeDownlinkScores measures the cost of only scoring the downlink
+// options of all gateways that received an uplink, without building them.
+func BenchmarkComputeDownlinkScores(b *testing.B) {
+	for _, numGateways := range []int{1, 10, 100} {
+		for _, occupancy := range []int{0, 10, 100} {
+			b.Run(fmt.Sprintf("Gateways=%d/Occupancy=%d", numGateways, occupancy), func(b *testing.B) {
+				benchmarkComputeDownlinkScores(b, numGateways, occupancy)
+			})
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// dutyCycleBand is a frequency range with a maximum duty cycle
+type dutyCycleBand struct {
+	name string
+	min  uint64
+	max  uint64
+	duty float64
+}
+
+// European Duty Cycle
+var euDutyCycleBands = []dutyCycleBand{
+	{"g", 863000000, 868000000, 0.01},   // g 863.0 – 868.0 MHz 1%
+	{"g1", 868000000, 868600000, 0.01},  // g1 868.0 – 868.6 MHz 1%
+	{"g2", 868700000, 869200000, 0.001}, // g2 868.7 – 869.2 MHz 0.1%
+	{"g3", 869400000, 869650000, 0.1},   // g3 869.4 – 869.65 MHz 10%
+	{"g4", 869700000, 870000000, 0.01},  // g4 869.7 – 870.0 MHz 1%
+}
+
+// getDutyCycleBand returns the duty cycle band of the given frequency in the
+// given region. If transmissions on the frequency are forbidden, allowed is
+// false. Regions without duty cycle bands return an empty band.
+func getDutyCycleBand(region string, frequency uint64) (dutyCycleBand dutyCycleBand, allowed bool) {
+	if region != "EU_863_870" {
+		return dutyCycleBand, true
+	}
+	for _, dutyCycleBand := range euDutyCycleBands {
+		if frequency >= dutyCycleBand.min && frequency < dutyCycleBand.max {
+			return dutyCycleBand, true
+		}
+	}
+	return dutyCycleBand, false
+}
+
+// getDutyCycle returns the maximum duty cycle for transmissions on the given
+// frequency in the given region. A duty cycle of 0 means that there is no
+// limit. If transmissions on the frequency are forbidden, allowed is false.
+func getDutyCycle(region string, frequency uint64) (duty float64, allowed bool) {
+	dutyCycleBand, allowed := getDutyCycleBand(region, frequency)
+	return dutyCycleBand.duty, allowed
+}
+
+// getGatewayDutyCycle returns the maximum duty cycle of the gateway for
+// transmissions on the given frequency. If the gateway has a duty cycle
+// override for the sub-band of the frequency, it replaces the limit of that
+// sub-band.
+func getGatewayDutyCycle(gtw *gateway.Gateway, region string, frequency uint64) (duty float64, allowed bool) {
+	dutyCycleBand, allowed := getDutyCycleBand(region, frequency)
+	duty = dutyCycleBand.duty
+	if override, ok := gtw.DutyCycleOverrides[dutyCycleBand.name]; allowed && duty > 0 && ok {
+		duty = override
+	}
+	return
+}
+
+// validateDutyCycleOverrides checks that the duty cycle overrides in the
+// attributes of gateways are for known sub-bands
+func validateDutyCycleOverrides(attributes map[string]gateway.Attributes) error {
+	for gatewayID, attributes := range attributes {
+	overrides:
+		for name := range attributes.DutyCycle {
+			for _, dutyCycleBand := range euDutyCycleBands {
+				if dutyCycleBand.name == name {
+					continue overrides
+				}
+			}
+			return errors.NewErrInvalidArgument("Duty cycle", fmt.Sprintf("sub-band %s of gateway %s is unknown", name, gatewayID))
+		}
+	}
+	return nil
+}
+
+// isSaturated returns true if the gateway can not transmit on the given
+// frequency because transmissions are forbidden or because the duty cycle is
+// exceeded.
+func isSaturated(gtw *gateway.Gateway, plan band.FrequencyPlan, frequency uint64) bool {
+	duty, allowed := getGatewayDutyCycle(gtw, plan.Region, frequency)
+	if !allowed {
+		return true
+	}
+	if !plan.DutyCycle || duty == 0 {
+		return false
+	}
+	return gtw.ChannelTx(frequency) > duty
+}
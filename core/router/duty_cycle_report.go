@@ -0,0 +1,152 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"sort"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+)
+
+// dutyCycleWindow is the observation period over which the duty cycle of a
+// sub-band is measured
+const dutyCycleWindow = time.Hour
+
+// DutyCycleReport contains the airtime of all gateways between From and To
+type DutyCycleReport struct {
+	From     time.Time
+	To       time.Time
+	Gateways []*GatewayDutyCycleReport
+}
+
+// Compliant returns true if no gateway exceeded the duty cycle of a sub-band
+func (r *DutyCycleReport) Compliant() bool {
+	for _, gtw := range r.Gateways {
+		for _, subBand := range gtw.SubBands {
+			if !subBand.Compliant() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// GatewayDutyCycleReport contains the airtime of a gateway per sub-band
+type GatewayDutyCycleReport struct {
+	GatewayID string
+	SubBands  []*SubBandDutyCycleReport
+}
+
+// SubBandDutyCycleReport contains the airtime of a gateway in a sub-band. In
+// regions without duty cycle sub-bands, each frequency is reported separately.
+type SubBandDutyCycleReport struct {
+	MinFrequency uint64
+	MaxFrequency uint64
+	// Limit is the maximum duty cycle in the sub-band (0 means no limit)
+	Limit float64
+	// Airtime is the total airtime in the sub-band
+	Airtime time.Duration
+	// Average is the duty cycle over the entire period of the report
+	Average float64
+	// Peak is the highest duty cycle over any observation period (one hour,
+	// or the period of the report if that is shorter)
+	Peak float64
+
+	records []gateway.AirtimeRecord
+}
+
+// Compliant returns true if the peak duty cycle does not exceed the limit
+func (s *SubBandDutyCycleReport) Compliant() bool {
+	return s.Limit == 0 || s.Peak <= s.Limit
+}
+
+// computePeak computes the highest duty cycle of the sorted records over any
+// observation period of the given length
+func (s *SubBandDutyCycleReport) computePeak(window time.Duration) {
+	var airtime time.Duration
+	var end int
+	for start, record := range s.records {
+		for ; end < len(s.records) && s.records[end].Time.Before(record.Time.Add(window)); end++ {
+			airtime += s.records[end].Airtime
+		}
+		if duty := float64(airtime) / float64(window); duty > s.Peak {
+			s.Peak = duty
+		}
+		airtime -= s.records[start].Airtime
+	}
+}
+
+// DutyCycleReport aggregates the airtime of all gateways between from and to
+// per sub-band
+func (r *router) DutyCycleReport(from, to time.Time) *DutyCycleReport {
+	report := &DutyCycleReport{From: from, To: to}
+	period := to.Sub(from)
+	if period <= 0 {
+		return report
+	}
+	window := dutyCycleWindow
+	if period < window {
+		window = period
+	}
+
+	r.gatewaysLock.RLock()
+	gateways := make([]*gateway.Gateway, 0, len(r.gateways))
+	for _, gtw := range r.gateways {
+		gateways = append(gateways, gtw)
+	}
+	r.gatewaysLock.RUnlock()
+
+	for _, gtw := range gateways {
+		records := gtw.Airtime(from, to)
+		if len(records) == 0 {
+			continue
+		}
+		subBands := make(map[uint64]*SubBandDutyCycleReport)
+		for _, record := range records {
+			subBand := getDutyCycleSubBand(gtw, record.Frequency)
+			if existing, ok := subBands[subBand.MinFrequency]; ok {
+				subBand = existing
+			} else {
+				subBands[subBand.MinFrequency] = subBand
+			}
+			subBand.Airtime += record.Airtime
+			subBand.records = append(subBand.records, record)
+		}
+		gtwReport := &GatewayDutyCycleReport{GatewayID: gtw.ID}
+		for _, subBand := range subBands {
+			subBand.Average = float64(subBand.Airtime) / float64(period)
+			subBand.computePeak(window)
+			gtwReport.SubBands = append(gtwReport.SubBands, subBand)
+		}
+		sort.Sort(subBandReports(gtwReport.SubBands))
+		report.Gateways = append(report.Gateways, gtwReport)
+	}
+	sort.Sort(gatewayReports(report.Gateways))
+
+	return report
+}
+
+// getDutyCycleSubBand returns an empty report for the duty cycle sub-band of
+// the frequency
+func getDutyCycleSubBand(gtw *gateway.Gateway, frequency uint64) *SubBandDutyCycleReport {
+	region := getRegion(gtw, frequency)
+	duty, _ := getGatewayDutyCycle(gtw, region, frequency)
+	if dutyCycleBand, _ := getDutyCycleBand(region, frequency); dutyCycleBand.max != 0 {
+		return &SubBandDutyCycleReport{MinFrequency: dutyCycleBand.min, MaxFrequency: dutyCycleBand.max, Limit: duty}
+	}
+	return &SubBandDutyCycleReport{MinFrequency: frequency, MaxFrequency: frequency, Limit: duty}
+}
+
+type gatewayReports []*GatewayDutyCycleReport
+
+func (r gatewayReports) Len() int           { return len(r) }
+func (r gatewayReports) Less(i, j int) bool { return r[i].GatewayID < r[j].GatewayID }
+func (r gatewayReports) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+type subBandReports []*SubBandDutyCycleReport
+
+func (r subBandReports) Len() int           { return len(r) }
+func (r subBandReports) Less(i, j int) bool { return r[i].MinFrequency < r[j].MinFrequency }
+func (r subBandReports) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
@@ -0,0 +1,63 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestDutyCycleReport(t *testing.T) {
+	a := New(t)
+
+	r := &router{
+		Component: &component.Component{
+			Ctx: GetLogger(t, "TestDutyCycleReport"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+
+	gtw := r.getGateway("eui-0102030405060708")
+	gtw.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	from := time.Now().Add(-2 * time.Hour)
+	to := from.Add(2 * time.Hour)
+
+	// 40 seconds in the first hour on 868.1 MHz (g1, 1%)
+	for i := 0; i < 10; i++ {
+		gtw.RecordAirtime(from.Add(time.Duration(i)*time.Minute), 868100000, 4*time.Second)
+	}
+	// 18 seconds in the second hour on 869.525 MHz (g3, 10%)
+	gtw.RecordAirtime(from.Add(90*time.Minute), 869525000, 18*time.Second)
+	// Outside the period of the report
+	gtw.RecordAirtime(to.Add(time.Minute), 869525000, 18*time.Second)
+
+	report := r.DutyCycleReport(from, to)
+	a.So(report.Gateways, ShouldHaveLength, 1)
+	a.So(report.Gateways[0].GatewayID, ShouldEqual, "eui-0102030405060708")
+	subBands := report.Gateways[0].SubBands
+	a.So(subBands, ShouldHaveLength, 2)
+
+	a.So(subBands[0].MinFrequency, ShouldEqual, 868000000)
+	a.So(subBands[0].Limit, ShouldEqual, 0.01)
+	a.So(subBands[0].Airtime, ShouldEqual, 40*time.Second)
+	a.So(subBands[0].Average, ShouldAlmostEqual, 40.0/7200, 0.00001)
+	a.So(subBands[0].Peak, ShouldAlmostEqual, 40.0/3600, 0.00001)
+	a.So(subBands[0].Compliant(), ShouldBeFalse)
+
+	a.So(subBands[1].MinFrequency, ShouldEqual, 869400000)
+	a.So(subBands[1].Limit, ShouldEqual, 0.1)
+	a.So(subBands[1].Airtime, ShouldEqual, 18*time.Second)
+	a.So(subBands[1].Average, ShouldAlmostEqual, 18.0/7200, 0.00001)
+	a.So(subBands[1].Peak, ShouldAlmostEqual, 18.0/3600, 0.00001)
+	a.So(subBands[1].Compliant(), ShouldBeTrue)
+
+	a.So(report.Compliant(), ShouldBeFalse)
+}
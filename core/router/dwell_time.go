@@ -0,0 +1,32 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"time"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ErrDwellTimeExceeded is returned when a downlink exceeds the dwell time of
+// the region, even at the fastest data rate
+var ErrDwellTimeExceeded = errors.New("Downlink exceeds dwell time at all data rates")
+
+// minTimeOnAir returns the time on air of a payload of the given size (in
+// bytes) at the fastest data rate of the frequency plan that uses the given
+// modulation
+func minTimeOnAir(plan band.FrequencyPlan, modulation pb_lorawan.Modulation, payloadSize uint) (min time.Duration) {
+	for _, dataRate := range plan.DataRates {
+		lorawan := &pb_lorawan.TxConfiguration{CodingRate: "4/5"}
+		if err := lorawan.SetDataRate(dataRate); err != nil || lorawan.Modulation != modulation {
+			continue
+		}
+		if t := computeTimeOnAir(lorawan, payloadSize); t > 0 && (min == 0 || t < min) {
+			min = t
+		}
+	}
+	return
+}
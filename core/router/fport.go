@@ -0,0 +1,20 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import "github.com/brocaar/lorawan"
+
+// fPortFromPayload returns the FPort of a LoRaWAN data message. The FPort is
+// not present if the message has no FRMPayload.
+func fPortFromPayload(payload []byte) (fPort uint8, ok bool) {
+	var phyPayload lorawan.PHYPayload
+	if err := phyPayload.UnmarshalBinary(payload); err != nil {
+		return
+	}
+	macPayload, ok := phyPayload.MACPayload.(*lorawan.MACPayload)
+	if !ok || macPayload.FPort == nil {
+		return 0, false
+	}
+	return *macPayload.FPort, true
+}
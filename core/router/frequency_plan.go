@@ -0,0 +1,132 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/apex/log"
+)
+
+// getRegion returns the region of the gateway. If the gateway did not report
+// its region, it is guessed from the given (uplink) frequency. If the frequency
+// is used in multiple regions, the region of the gateway always wins.
+func getRegion(gtw *gateway.Gateway, frequency uint64) string {
+	gatewayStatus, _ := gtw.Status.Get() // This just returns empty if non-existing
+	regions := band.GuessAll(frequency)
+	if gatewayStatus.Region != "" {
+		if len(regions) > 1 {
+			gtw.Ctx.WithFields(log.Fields{
+				"Frequency": frequency,
+				"Regions":   regions,
+				"Region":    gatewayStatus.Region,
+			}).Debug("Ambiguous frequency, using region of gateway")
+		}
+		return gatewayStatus.Region
+	}
+	if len(regions) == 0 {
+		return ""
+	}
+	if len(regions) > 1 {
+		gtw.Ctx.WithFields(log.Fields{
+			"Frequency": frequency,
+			"Regions":   regions,
+			"Region":    regions[0],
+		}).Warn("Ambiguous frequency, guessing region")
+	}
+	return regions[0]
+}
+
+// loadFrequencyPlans loads the frequency plans of a list of region=filename
+// pairs, where the file contains the override of the built-in frequency plan
+// of the region
+func loadFrequencyPlans(in []string) (map[string]band.FrequencyPlan, error) {
+	plans := make(map[string]band.FrequencyPlan)
+	for _, pair := range in {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.NewErrInvalidArgument("Frequency plan", fmt.Sprintf("%s is not a region=filename pair", pair))
+		}
+		plan, err := band.Get(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		override, err := band.ReadFrequencyPlanOverride(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not read frequency plan of %s", parts[0])
+		}
+		if err := override.Apply(&plan); err != nil {
+			return nil, errors.Wrapf(err, "Invalid frequency plan of %s", parts[0])
+		}
+		plans[parts[0]] = plan
+	}
+	return plans, nil
+}
+
+// validateFrequencyPlans checks that the configured sub-bands and TX power
+// indices can be applied to the frequency plans of their regions. Otherwise the
+// router would not be able to build downlink options in those regions.
+func (r *router) validateFrequencyPlans() error {
+	for region := range r.subBands {
+		if _, err := r.getFrequencyPlan(region); err != nil {
+			return errors.Wrapf(err, "Invalid sub-bands for %s", region)
+		}
+	}
+	for region := range r.txPowerIndices {
+		if _, err := r.getFrequencyPlan(region); err != nil {
+			return errors.Wrapf(err, "Invalid TX power index for %s", region)
+		}
+	}
+	return nil
+}
+
+// getFrequencyPlan returns the frequency plan for the region
+func (r *router) getFrequencyPlan(region string) (plan band.FrequencyPlan, err error) {
+	plan, ok := r.frequencyPlans[region]
+	if !ok {
+		plan, err = band.Get(region)
+		if err != nil {
+			return
+		}
+	}
+	if subBands, ok := r.subBands[region]; ok {
+		if err = plan.SetSubBands(subBands...); err != nil {
+			return
+		}
+	}
+	if offset, ok := r.rx1DROffsets[region]; ok && len(offset) > 0 {
+		plan.RX1DROffset = offset[0]
+	}
+	if index, ok := r.txPowerIndices[region]; ok && len(index) > 0 {
+		if _, err = plan.GetTXPower(index[0]); err != nil {
+			return
+		}
+		plan.DefaultTXPowerIndex = index[0]
+	}
+	return
+}
+
+// Reasons why transmissions on a frequency are forbidden
+const (
+	forbiddenOutsideSubBands = "outside the sub-bands of the region"
+	forbiddenByConfiguration = "forbidden in the region"
+)
+
+// forbiddenReason returns the reason why transmissions on the frequency are
+// forbidden in the region, or an empty string if they are allowed
+func (r *router) forbiddenReason(region string, frequency uint64) string {
+	if _, allowed := getDutyCycle(region, frequency); !allowed {
+		return forbiddenOutsideSubBands
+	}
+	for _, forbidden := range r.forbiddenFrequencies[region] {
+		if uint64(forbidden) == frequency {
+			return forbiddenByConfiguration
+		}
+	}
+	return ""
+}
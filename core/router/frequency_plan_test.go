@@ -0,0 +1,81 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestGetRegion(t *testing.T) {
+	a := New(t)
+
+	// Without region of the gateway, the region is guessed
+	a.So(getRegion(newReferenceGateway(t, ""), 868100000), ShouldEqual, "EU_863_870")
+	a.So(getRegion(newReferenceGateway(t, ""), 923200000), ShouldEqual, "AS_923")
+
+	// The region of the gateway wins for overlapping frequencies
+	a.So(getRegion(newReferenceGateway(t, "AU_915_928"), 923200000), ShouldEqual, "AU_915_928")
+	a.So(getRegion(newReferenceGateway(t, "KR_920_923"), 923200000), ShouldEqual, "KR_920_923")
+}
+
+func TestLoadFrequencyPlans(t *testing.T) {
+	a := New(t)
+
+	file, err := ioutil.TempFile("", "frequency-plan-override")
+	a.So(err, ShouldBeNil)
+	defer os.Remove(file.Name())
+	file.WriteString(`duty-cycle: false
+dwell-time: 400ms
+`)
+	file.Close()
+
+	plans, err := loadFrequencyPlans([]string{"EU_863_870=" + file.Name()})
+	a.So(err, ShouldBeNil)
+	a.So(plans["EU_863_870"].DutyCycle, ShouldBeFalse)
+	a.So(plans["EU_863_870"].DwellTime, ShouldEqual, 400*time.Millisecond)
+
+	// The region values are applied on top of the loaded frequency plan
+	r := &router{
+		frequencyPlans: plans,
+		rx1DROffsets:   map[string][]int{"EU_863_870": {1}},
+	}
+	plan, err := r.getFrequencyPlan("EU_863_870")
+	a.So(err, ShouldBeNil)
+	a.So(plan.DutyCycle, ShouldBeFalse)
+	a.So(plan.RX1DROffset, ShouldEqual, 1)
+
+	_, err = loadFrequencyPlans([]string{"EU_863_870"})
+	a.So(err, ShouldNotBeNil)
+	_, err = loadFrequencyPlans([]string{"XX_000_000=" + file.Name()})
+	a.So(err, ShouldNotBeNil)
+	_, err = loadFrequencyPlans([]string{"EU_863_870=" + file.Name() + ".missing"})
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestValidateFrequencyPlans(t *testing.T) {
+	a := New(t)
+
+	r := &router{subBands: map[string][]int{"US_902_928": {2}}}
+	a.So(r.validateFrequencyPlans(), ShouldBeNil)
+
+	// Sub-bands that are out of range
+	r = &router{subBands: map[string][]int{"US_902_928": {9}}}
+	a.So(r.validateFrequencyPlans(), ShouldNotBeNil)
+
+	// Regions without sub-bands
+	r = &router{subBands: map[string][]int{"EU_863_870": {1}}}
+	a.So(r.validateFrequencyPlans(), ShouldNotBeNil)
+
+	r = &router{txPowerIndices: map[string][]int{"EU_863_870": {7}}}
+	a.So(r.validateFrequencyPlans(), ShouldBeNil)
+
+	// TX power indices that are out of range
+	r = &router{txPowerIndices: map[string][]int{"EU_863_870": {8}}}
+	a.So(r.validateFrequencyPlans(), ShouldNotBeNil)
+}
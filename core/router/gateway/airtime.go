@@ -0,0 +1,133 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
+)
+
+// AirtimeRetention is the time that transmissions are kept in the airtime log
+var AirtimeRetention = 24 * time.Hour
+
+// AirtimeRecord is a transmission in the airtime log of a gateway
+type AirtimeRecord struct {
+	Time      time.Time
+	Frequency uint64
+	Airtime   time.Duration
+}
+
+type airtimeRecords []AirtimeRecord
+
+func (r airtimeRecords) Len() int           { return len(r) }
+func (r airtimeRecords) Less(i, j int) bool { return r[i].Time.Before(r[j].Time) }
+func (r airtimeRecords) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+// airtimeLog keeps track of the transmissions of a gateway
+type airtimeLog struct {
+	sync.RWMutex
+	records airtimeRecords
+}
+
+func (l *airtimeLog) add(record AirtimeRecord) {
+	l.Lock()
+	defer l.Unlock()
+	retained := l.records[:0]
+	for _, r := range l.records {
+		if time.Since(r.Time) < AirtimeRetention {
+			retained = append(retained, r)
+		}
+	}
+	l.records = append(retained, record)
+}
+
+// remove removes the most recent transmission with the same frequency and
+// airtime as the record
+func (l *airtimeLog) remove(record AirtimeRecord) {
+	l.Lock()
+	defer l.Unlock()
+	for i := len(l.records) - 1; i >= 0; i-- {
+		if l.records[i].Frequency == record.Frequency && l.records[i].Airtime == record.Airtime {
+			l.records = append(l.records[:i], l.records[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *airtimeLog) get(from, to time.Time) []AirtimeRecord {
+	l.RLock()
+	defer l.RUnlock()
+	var records airtimeRecords
+	for _, r := range l.records {
+		if !r.Time.Before(from) && r.Time.Before(to) {
+			records = append(records, r)
+		}
+	}
+	sort.Sort(records)
+	return records
+}
+
+// RecordAirtime adds a transmission to the airtime log of the gateway
+func (g *Gateway) RecordAirtime(t time.Time, frequency uint64, airtime time.Duration) {
+	g.airtime.add(AirtimeRecord{Time: t, Frequency: frequency, Airtime: airtime})
+}
+
+// Airtime returns the transmissions in the airtime log of the gateway that
+// started between from (inclusive) and to (exclusive), ordered by time
+func (g *Gateway) Airtime(from, to time.Time) []AirtimeRecord {
+	return g.airtime.get(from, to)
+}
+
+// addTx updates the utilization and the airtime log of the gateway for
+// transmitting a downlink message
+func (g *Gateway) addTx(downlink *pb_router.DownlinkMessage) error {
+	if err := g.Utilization.AddTx(downlink); err != nil {
+		return err
+	}
+	t, err := downlinkAirtime(downlink)
+	if err != nil || t == 0 {
+		return err
+	}
+	g.RecordAirtime(time.Now(), downlink.GatewayConfiguration.Frequency, t)
+	return nil
+}
+
+// removeTx reverts addTx for a downlink message that was not transmitted
+func (g *Gateway) removeTx(downlink *pb_router.DownlinkMessage) error {
+	if err := g.Utilization.RemoveTx(downlink); err != nil {
+		return err
+	}
+	t, err := downlinkAirtime(downlink)
+	if err != nil || t == 0 {
+		return err
+	}
+	g.airtime.remove(AirtimeRecord{Frequency: downlink.GatewayConfiguration.Frequency, Airtime: t})
+	return nil
+}
+
+// TimeOnAir calculates the time on air of a LoRaWAN payload of the given size
+// (in bytes)
+func TimeOnAir(lorawan *pb_lorawan.TxConfiguration, payloadSize uint) (t time.Duration, err error) {
+	switch lorawan.Modulation {
+	case pb_lorawan.Modulation_LORA:
+		t, err = toa.ComputeLoRa(payloadSize, lorawan.DataRate, lorawan.CodingRate)
+	case pb_lorawan.Modulation_FSK:
+		t, err = toa.ComputeFSK(payloadSize, int(lorawan.BitRate))
+	}
+	return
+}
+
+// downlinkAirtime returns the time on air of a downlink message
+func downlinkAirtime(downlink *pb_router.DownlinkMessage) (time.Duration, error) {
+	lorawan := downlink.ProtocolConfiguration.GetLorawan()
+	if lorawan == nil {
+		return 0, nil
+	}
+	return TimeOnAir(lorawan, uint(len(downlink.Payload)))
+}
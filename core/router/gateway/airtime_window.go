@@ -0,0 +1,70 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"sync"
+	"time"
+)
+
+// AirtimeQuotaWindow is the rolling window over which downlink airtime quotas
+// are accounted
+const AirtimeQuotaWindow = time.Hour
+
+type airtimeUsage struct {
+	time    time.Time
+	airtime time.Duration
+}
+
+// AirtimeWindow keeps track of the airtime that is used per key (for example a
+// network or a priority class) over the last AirtimeQuotaWindow
+type AirtimeWindow struct {
+	usageLock sync.Mutex
+	usage     map[string][]airtimeUsage
+}
+
+// used returns the airtime that the key used in the window. It should be
+// called with the lock held.
+func (w *AirtimeWindow) used(key string, now time.Time) (used time.Duration) {
+	usage := w.usage[key]
+	var i int
+	for i < len(usage) && now.Sub(usage[i].time) > AirtimeQuotaWindow {
+		i++
+	}
+	usage = usage[i:]
+	w.usage[key] = usage
+	for _, u := range usage {
+		used += u.airtime
+	}
+	return
+}
+
+// Consume adds the airtime to the usage of the key if the usage in the window
+// does not exceed the quota afterwards. It returns false otherwise.
+func (w *AirtimeWindow) Consume(key string, airtime, quota time.Duration, now time.Time) bool {
+	w.usageLock.Lock()
+	defer w.usageLock.Unlock()
+	if w.usage == nil {
+		w.usage = make(map[string][]airtimeUsage)
+	}
+	if w.used(key, now)+airtime > quota {
+		return false
+	}
+	w.usage[key] = append(w.usage[key], airtimeUsage{time: now, airtime: airtime})
+	return true
+}
+
+// Refund removes the most recent usage of the airtime from the usage of the
+// key. It is used for downlinks that were charged but not scheduled.
+func (w *AirtimeWindow) Refund(key string, airtime time.Duration) {
+	w.usageLock.Lock()
+	defer w.usageLock.Unlock()
+	usage := w.usage[key]
+	for i := len(usage) - 1; i >= 0; i-- {
+		if usage[i].airtime == airtime {
+			w.usage[key] = append(usage[:i], usage[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// attemptsTimeout is the time after which unacknowledged attempts are forgotten
+const attemptsTimeout = time.Minute
+
+// sentTimeout is the time after which downlinks that were sent to the gateway
+// are no longer refunded if the gateway reports that it did not transmit them
+const sentTimeout = time.Minute
+
+// attemptGroup is a downlink that is scheduled in multiple slots, of which only
+// one is expected to be transmitted
+type attemptGroup struct {
+	identifiers []string
+	downlinks   map[string]*pb_router.DownlinkMessage
+	createdAt   time.Time
+}
+
+// attempts keeps track of the attempt groups of a gateway
+type attempts struct {
+	sync.RWMutex
+	byIdentifier map[string]*attemptGroup
+	sweptAt      time.Time
+}
+
+func (a *attempts) add(group *attemptGroup) {
+	a.Lock()
+	defer a.Unlock()
+	now := time.Now()
+	if a.byIdentifier == nil {
+		a.byIdentifier = make(map[string]*attemptGroup)
+	}
+	if now.Sub(a.sweptAt) > attemptsTimeout {
+		for _, existing := range a.byIdentifier {
+			if now.Sub(existing.createdAt) > attemptsTimeout {
+				a.remove(existing)
+			}
+		}
+		a.sweptAt = now
+	}
+	for identifier := range group.downlinks {
+		a.byIdentifier[identifier] = group
+	}
+}
+
+// remove should be called with the lock held
+func (a *attempts) remove(group *attemptGroup) {
+	for identifier := range group.downlinks {
+		delete(a.byIdentifier, identifier)
+	}
+}
+
+func (a *attempts) get(identifier string) *attemptGroup {
+	a.RLock()
+	defer a.RUnlock()
+	return a.byIdentifier[identifier]
+}
+
+// sentDownlink is a downlink that was sent to the gateway and charged, but not
+// yet acknowledged
+type sentDownlink struct {
+	downlink *pb_router.DownlinkMessage
+	sentAt   time.Time
+}
+
+// sentDownlinks keeps track of the downlinks that were sent to a gateway, so
+// that they can be refunded if the gateway does not transmit them
+type sentDownlinks struct {
+	sync.Mutex
+	byIdentifier map[string]sentDownlink
+	sweptAt      time.Time
+}
+
+func (s *sentDownlinks) add(downlink *pb_router.DownlinkMessage) {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	if s.byIdentifier == nil {
+		s.byIdentifier = make(map[string]sentDownlink)
+	}
+	if now.Sub(s.sweptAt) > sentTimeout {
+		for identifier, sent := range s.byIdentifier {
+			if now.Sub(sent.sentAt) > sentTimeout {
+				delete(s.byIdentifier, identifier)
+			}
+		}
+		s.sweptAt = now
+	}
+	s.byIdentifier[downlink.Identifier] = sentDownlink{downlink: downlink, sentAt: now}
+}
+
+// remove returns and removes the sent downlink with the given identifier
+func (s *sentDownlinks) remove(identifier string) (*pb_router.DownlinkMessage, bool) {
+	s.Lock()
+	defer s.Unlock()
+	sent, ok := s.byIdentifier[identifier]
+	if !ok || time.Since(sent.sentAt) > sentTimeout {
+		return nil, false
+	}
+	delete(s.byIdentifier, identifier)
+	return sent.downlink, true
+}
+
+// HandleDownlinkAttempts schedules a downlink in multiple slots (for example
+// RX1 and RX2), in order of preference. Each slot is booked in the schedule,
+// but later slots are cancelled as soon as the transmission in an earlier slot
+// is acknowledged. Like any downlink, each attempt is charged when it is sent
+// to the gateway and refunded if the gateway did not transmit it.
+func (g *Gateway) HandleDownlinkAttempts(identifiers []string, downlinks []*pb_router.DownlinkMessage) (err error) {
+	_, span := g.tracer().Start(context.Background(), "gateway.HandleDownlinkAttempts", trace.WithAttributes(
+		attribute.String("gateway_id", g.ID),
+		attribute.Int("attempts", len(identifiers)),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
+	if len(identifiers) == 0 || len(identifiers) != len(downlinks) {
+		return errors.NewErrInvalidArgument("Downlink attempts", "need an identifier for each downlink")
+	}
+	group := &attemptGroup{
+		identifiers: identifiers,
+		downlinks:   make(map[string]*pb_router.DownlinkMessage),
+		createdAt:   time.Now(),
+	}
+	for i, identifier := range identifiers {
+		group.downlinks[identifier] = downlinks[i]
+	}
+	g.attempts.add(group)
+	for i, identifier := range identifiers {
+		if err := g.HandleDownlink(identifier, downlinks[i]); err != nil {
+			for _, scheduled := range identifiers[:i] {
+				g.Schedule.Cancel(scheduled)
+			}
+			g.attempts.Lock()
+			g.attempts.remove(group)
+			g.attempts.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleSent updates the utilization for a downlink that was sent to the
+// gateway. Gateways that do not acknowledge their transmissions are charged
+// for every downlink that is sent to them.
+func (g *Gateway) HandleSent(downlink *pb_router.DownlinkMessage) error {
+	if downlink.Identifier != "" {
+		g.sent.add(downlink)
+	}
+	return g.addTx(downlink)
+}
+
+// HandleTxAck handles the acknowledgement of the gateway for the transmission
+// of the downlink with the given identifier. If err is not nil, the gateway
+// did not transmit the downlink.
+func (g *Gateway) HandleTxAck(identifier string, err error) error {
+	ctx := g.Ctx.WithField("Identifier", identifier)
+	group := g.attempts.get(identifier)
+
+	g.txAcks.add(err == nil)
+	sent, wasSent := g.sent.remove(identifier)
+
+	_, span := g.tracer().Start(context.Background(), "gateway.HandleTxAck", trace.WithAttributes(
+		attribute.String("gateway_id", g.ID),
+		attribute.String("identifier", identifier),
+		attribute.Bool("attempt", group != nil),
+		attribute.Bool("transmitted", err == nil),
+		attribute.Bool("refunded", err != nil && wasSent),
+	))
+	defer span.End()
+
+	if err != nil {
+		ctx.WithError(err).Warn("Gateway did not transmit downlink")
+		if isPowerError(err) {
+			g.handlePowerError(identifier)
+		}
+		if wasSent {
+			if err := g.removeTx(sent); err != nil {
+				ctx.WithError(err).Warn("Could not refund downlink")
+			}
+		}
+		if group != nil && group.identifiers[len(group.identifiers)-1] == identifier {
+			g.attempts.Lock()
+			g.attempts.remove(group)
+			g.attempts.Unlock()
+		}
+		return nil
+	}
+
+	if group == nil {
+		return nil
+	}
+
+	g.attempts.Lock()
+	g.attempts.remove(group)
+	g.attempts.Unlock()
+
+	var cancelled int
+	for _, other := range group.identifiers {
+		if other != identifier && g.Schedule.Cancel(other) {
+			ctx.WithField("Cancelled", other).Debug("Cancelled downlink attempt")
+			cancelled++
+		}
+	}
+	span.SetAttributes(attribute.Int("cancelled", cancelled))
+
+	return nil
+}
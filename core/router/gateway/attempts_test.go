@@ -0,0 +1,101 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestHandleDownlinkAttempts(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestHandleDownlinkAttempts"), "eui-0102030405060708")
+	gtw.Schedule.Sync(0)
+
+	rx1ID, _ := gtw.Schedule.GetOption(1000000, 100000)
+	rx2ID, _ := gtw.Schedule.GetOption(2000000, 100000)
+	rx1 := buildDownlink(8681000000)
+	rx1.Identifier = rx1ID
+	rx2 := buildDownlink(8695250000)
+	rx2.Identifier = rx2ID
+
+	err := gtw.HandleDownlinkAttempts([]string{rx1ID}, nil)
+	a.So(err, ShouldNotBeNil)
+
+	err = gtw.HandleDownlinkAttempts([]string{rx1ID, rx2ID}, []*pb.DownlinkMessage{rx1, rx2})
+	a.So(err, ShouldBeNil)
+
+	// Both attempts are booked
+	s := gtw.Schedule.(*schedule)
+	a.So(s.getConflicts(1000000, 100000), ShouldEqual, 100)
+	a.So(s.getConflicts(2000000, 100000), ShouldEqual, 100)
+
+	// Sending an attempt to the gateway charges airtime, even if the gateway
+	// never acknowledges it
+	gtw.HandleSent(rx1)
+	gtw.Utilization.Tick()
+	_, tx := gtw.Utilization.GetChannel(8681000000)
+	a.So(tx, ShouldBeGreaterThan, 0)
+	a.So(gtw.Airtime(time.Now().Add(-time.Minute), time.Now().Add(time.Minute)), ShouldHaveLength, 1)
+
+	// The acknowledgement does not charge the attempt again
+	err = gtw.HandleTxAck(rx1ID, nil)
+	a.So(err, ShouldBeNil)
+	a.So(gtw.Airtime(time.Now().Add(-time.Minute), time.Now().Add(time.Minute)), ShouldHaveLength, 1)
+	_, tx = gtw.Utilization.GetChannel(8695250000)
+	a.So(tx, ShouldEqual, 0)
+
+	// The other attempt is cancelled
+	a.So(s.getConflicts(2000000, 100000), ShouldEqual, 0)
+	a.So(gtw.Schedule.Cancel(rx2ID), ShouldBeFalse)
+	a.So(gtw.attempts.get(rx2ID), ShouldBeNil)
+}
+
+func TestHandleTxAckRefund(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestHandleTxAckRefund"), "eui-0102030405060708")
+
+	downlink := buildDownlink(8681000000)
+	downlink.Identifier = "downlink"
+	gtw.HandleSent(downlink)
+	a.So(gtw.Airtime(time.Now().Add(-time.Minute), time.Now().Add(time.Minute)), ShouldHaveLength, 1)
+
+	// A downlink that the gateway did not transmit is refunded
+	gtw.HandleTxAck("downlink", errors.New("TOO_LATE"))
+	a.So(gtw.Airtime(time.Now().Add(-time.Minute), time.Now().Add(time.Minute)), ShouldBeEmpty)
+	gtw.Utilization.Tick()
+	_, tx := gtw.Utilization.GetChannel(8681000000)
+	a.So(tx, ShouldEqual, 0)
+
+	// It is only refunded once
+	gtw.HandleSent(buildDownlink(8681000000))
+	gtw.HandleTxAck("downlink", errors.New("TOO_LATE"))
+	a.So(gtw.Airtime(time.Now().Add(-time.Minute), time.Now().Add(time.Minute)), ShouldHaveLength, 1)
+}
+
+func TestTxAckSuccessRate(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestTxAckSuccessRate"), "eui-0102030405060708")
+
+	_, ok := gtw.TxAckSuccessRate()
+	a.So(ok, ShouldBeFalse)
+
+	gtw.HandleTxAck("first", nil)
+	gtw.HandleTxAck("second", errors.New("TOO_LATE"))
+	rate, ok := gtw.TxAckSuccessRate()
+	a.So(ok, ShouldBeTrue)
+	a.So(rate, ShouldEqual, 0.5)
+
+	// Only the most recent TxAcks are taken into account
+	for i := 0; i < txAckHistorySize; i++ {
+		gtw.HandleTxAck("next", nil)
+	}
+	rate, _ = gtw.TxAckSuccessRate()
+	a.So(rate, ShouldEqual, 1)
+}
@@ -0,0 +1,62 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Attributes are the properties of a gateway that are configured in the
+// router, because the gateway does not report them
+type Attributes struct {
+	// AntennaGain is the gain of the gateway antenna (in dBi). If it is not
+	// set, the gateway keeps DefaultAntennaGain.
+	AntennaGain *float64 `yaml:"antenna-gain"`
+	// CableLoss is the loss of the cable between the gateway and the antenna (in dB)
+	CableLoss float64 `yaml:"cable-loss"`
+	// FullDuplex is true if the gateway can receive while it is transmitting
+	FullDuplex bool `yaml:"full-duplex"`
+	// MaxRX1DataRate is the fastest data rate the gateway should use in RX1
+	MaxRX1DataRate *types.DataRate `yaml:"max-rx1-data-rate"`
+	// DutyCycle replaces the duty cycle limits (0-1) of sub-bands, by the name
+	// of the sub-band (for example g1)
+	DutyCycle map[string]float64 `yaml:"duty-cycle"`
+}
+
+// ReadAttributes reads the attributes of gateways from a YAML file that maps
+// gateway IDs to their attributes
+func ReadAttributes(filename string) (map[string]Attributes, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	attributes := make(map[string]Attributes)
+	if err := yaml.Unmarshal(data, &attributes); err != nil {
+		return nil, err
+	}
+	for gatewayID, attributes := range attributes {
+		for subBand, duty := range attributes.DutyCycle {
+			if duty <= 0 || duty > 1 {
+				return nil, errors.NewErrInvalidArgument("Duty cycle", fmt.Sprintf("%v of sub-band %s of gateway %s is not in (0, 1]", duty, subBand, gatewayID))
+			}
+		}
+	}
+	return attributes, nil
+}
+
+// SetAttributes sets the attributes of the gateway
+func (g *Gateway) SetAttributes(attributes Attributes) {
+	if attributes.AntennaGain != nil {
+		g.AntennaGain = *attributes.AntennaGain
+	}
+	g.CableLoss = attributes.CableLoss
+	g.FullDuplex = attributes.FullDuplex
+	g.MaxRX1DataRate = attributes.MaxRX1DataRate
+	g.DutyCycleOverrides = attributes.DutyCycle
+}
@@ -0,0 +1,78 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestReadAttributes(t *testing.T) {
+	a := New(t)
+
+	file, err := ioutil.TempFile("", "gateway-attributes")
+	a.So(err, ShouldBeNil)
+	defer os.Remove(file.Name())
+	file.WriteString(`eui-0102030405060708:
+  antenna-gain: 6
+  cable-loss: 1.5
+  full-duplex: true
+  max-rx1-data-rate: SF9BW125
+  duty-cycle:
+    g1: 0.05
+`)
+	file.Close()
+
+	attributes, err := ReadAttributes(file.Name())
+	a.So(err, ShouldBeNil)
+	a.So(attributes, ShouldHaveLength, 1)
+	a.So(*attributes["eui-0102030405060708"].AntennaGain, ShouldEqual, 6)
+	a.So(attributes["eui-0102030405060708"].CableLoss, ShouldEqual, 1.5)
+	a.So(attributes["eui-0102030405060708"].FullDuplex, ShouldBeTrue)
+	a.So(attributes["eui-0102030405060708"].MaxRX1DataRate.String(), ShouldEqual, "SF9BW125")
+	a.So(attributes["eui-0102030405060708"].DutyCycle, ShouldResemble, map[string]float64{"g1": 0.05})
+
+	gtw := NewGateway(GetLogger(t, "TestReadAttributes"), "eui-0102030405060708")
+	gtw.SetAttributes(attributes["eui-0102030405060708"])
+	power, _ := gtw.TXPower(20)
+	a.So(power, ShouldEqual, 15) // 20 - 6 + 1.5
+	a.So(gtw.FullDuplex, ShouldBeTrue)
+
+	// Without antenna gain, the gateway keeps the default antenna gain
+	gtw = NewGateway(GetLogger(t, "TestReadAttributes"), "eui-0807060504030201")
+	gtw.SetAttributes(Attributes{CableLoss: 1})
+	a.So(gtw.AntennaGain, ShouldEqual, DefaultAntennaGain)
+	power, _ = gtw.TXPower(20)
+	a.So(power, ShouldEqual, 19) // 20 - 2 + 1
+
+	_, err = ReadAttributes(file.Name() + ".missing")
+	a.So(err, ShouldNotBeNil)
+
+	// Invalid data rates are rejected when the file is read
+	invalid, err := ioutil.TempFile("", "gateway-attributes")
+	a.So(err, ShouldBeNil)
+	defer os.Remove(invalid.Name())
+	invalid.WriteString(`eui-0102030405060708:
+  max-rx1-data-rate: SF6BW125
+`)
+	invalid.Close()
+	_, err = ReadAttributes(invalid.Name())
+	a.So(err, ShouldNotBeNil)
+
+	// Duty cycles must be fractions
+	invalid, err = ioutil.TempFile("", "gateway-attributes")
+	a.So(err, ShouldBeNil)
+	defer os.Remove(invalid.Name())
+	invalid.WriteString(`eui-0102030405060708:
+  duty-cycle:
+    g1: 5
+`)
+	invalid.Close()
+	_, err = ReadAttributes(invalid.Name())
+	a.So(err, ShouldNotBeNil)
+}
@@ -0,0 +1,56 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"sync"
+	"time"
+)
+
+// BeaconPeriod is the period of the Class B beacons
+const BeaconPeriod = 128 * time.Second
+
+// gpsEpoch is the start of GPS time. GPS time does not have leap seconds, so it
+// is gpsLeapSeconds ahead of UTC.
+var gpsEpoch = time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+const gpsLeapSeconds = 18 * time.Second
+
+// gpsTime returns the time since the GPS epoch
+func gpsTime(t time.Time) time.Duration {
+	return t.Sub(gpsEpoch) + gpsLeapSeconds
+}
+
+// gpsSync is the time (in Unix nanoseconds) and the timestamp of the last
+// status of the gateway in which it was synchronized with GPS
+type gpsSync struct {
+	sync.RWMutex
+	time      int64
+	timestamp uint32
+}
+
+func (s *gpsSync) set(time int64, timestamp uint32) {
+	s.Lock()
+	defer s.Unlock()
+	s.time, s.timestamp = time, timestamp
+}
+
+func (s *gpsSync) get() (time int64, timestamp uint32) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.time, s.timestamp
+}
+
+// NextBeacon returns the time (in GPS seconds) of the first beacon after t,
+// and the timestamp of that beacon in the clock of the gateway. It returns
+// false if the gateway was never synchronized with GPS.
+func (g *Gateway) NextBeacon(t time.Time) (beaconTime uint32, timestamp uint32, ok bool) {
+	syncTime, syncTimestamp := g.gpsSync.get()
+	if syncTime == 0 {
+		return 0, 0, false
+	}
+	next := (gpsTime(t)/BeaconPeriod + 1) * BeaconPeriod
+	sinceSync := next - gpsTime(time.Unix(0, syncTime))
+	return uint32(next / time.Second), syncTimestamp + uint32(sinceSync/time.Microsecond), true
+}
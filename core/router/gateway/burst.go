@@ -0,0 +1,47 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"context"
+	"time"
+
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HandleDownlinkBurst schedules a burst of downlinks at the timestamps in their
+// gateway configuration. If one of the downlinks conflicts with a scheduled
+// transmission, none of them is scheduled.
+func (g *Gateway) HandleDownlinkBurst(downlinks []*pb_router.DownlinkMessage) (err error) {
+	_, span := g.tracer().Start(context.Background(), "gateway.HandleDownlinkBurst", trace.WithAttributes(
+		attribute.String("gateway_id", g.ID),
+		attribute.Int("frames", len(downlinks)),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
+	lengths := make([]uint32, len(downlinks))
+	for i, downlink := range downlinks {
+		airtime, err := downlinkAirtime(downlink)
+		if err != nil {
+			return err
+		}
+		lengths[i] = uint32(airtime / time.Microsecond)
+	}
+	identifiers, err := g.Schedule.ScheduleBurst(downlinks, lengths)
+	if err != nil {
+		g.Ctx.WithError(err).Warn("Could not schedule downlink burst")
+		return err
+	}
+	for i, downlink := range downlinks {
+		g.handleScheduled(identifiers[i], downlink)
+	}
+	return nil
+}
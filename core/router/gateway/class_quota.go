@@ -0,0 +1,37 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ErrClassQuotaExceeded is returned when a priority class exceeds its share of
+// the downlink airtime of a gateway
+var ErrClassQuotaExceeded = errors.New("Downlink airtime quota of priority class exceeded")
+
+// ConsumeClassAirtime charges the airtime of a downlink to its priority class.
+// If that would exceed the share of the class (between 0 and 1) of the
+// downlink airtime of the gateway, it returns ErrClassQuotaExceeded. A share
+// of 0 means that the class has no quota.
+func (g *Gateway) ConsumeClassAirtime(class string, airtime time.Duration, share float64) error {
+	if share == 0 {
+		return nil
+	}
+	if !g.classQuotas.Consume(class, airtime, time.Duration(share*float64(AirtimeQuotaWindow)), time.Now()) {
+		return ErrClassQuotaExceeded
+	}
+	return nil
+}
+
+// RefundClassAirtime refunds airtime that was charged to a priority class with
+// ConsumeClassAirtime for a downlink that was not scheduled after all
+func (g *Gateway) RefundClassAirtime(class string, airtime time.Duration, share float64) {
+	if share == 0 {
+		return
+	}
+	g.classQuotas.Refund(class, airtime)
+}
@@ -4,14 +4,22 @@
 package gateway
 
 import (
+	"sync/atomic"
 	"time"
 
 	pb "github.com/TheThingsNetwork/ttn/api/gateway"
 	pb_monitor "github.com/TheThingsNetwork/ttn/api/monitor"
 	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/apex/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// DefaultAntennaGain is the antenna gain (in dBi) of gateways that did not
+// configure their antenna gain. Assuming no gain at all would make gateways
+// with a typical antenna exceed the maximum EIRP.
+const DefaultAntennaGain = 2
+
 // NewGateway creates a new in-memory Gateway structure
 func NewGateway(ctx log.Interface, id string) *Gateway {
 	ctx = ctx.WithField("GatewayID", id)
@@ -20,6 +28,7 @@ func NewGateway(ctx log.Interface, id string) *Gateway {
 		Status:      NewStatusStore(),
 		Utilization: NewUtilization(),
 		Schedule:    NewSchedule(ctx),
+		AntennaGain: DefaultAntennaGain,
 		Ctx:         ctx,
 	}
 }
@@ -32,10 +41,57 @@ type Gateway struct {
 	Schedule    Schedule
 	LastSeen    time.Time
 
+	// AntennaGain is the gain of the gateway antenna (in dBi)
+	AntennaGain float64
+	// CableLoss is the loss of the cable between the gateway and the antenna (in dB)
+	CableLoss float64
+	// MinTXPower is the minimum conducted TX power of the gateway (in dBm)
+	MinTXPower int32
+	// FullDuplex is true if the gateway can receive while it is transmitting
+	FullDuplex bool
+	// RXOnly is true if the gateway can not transmit. Its uplink is still
+	// forwarded, but no downlink options are built for it.
+	RXOnly bool
+	// MaxRX1DataRate is the fastest data rate (for example SF9BW125) the
+	// gateway should use in RX1. This is useful for gateways with a backhaul
+	// that has too much jitter for short RX1 frames. Nil means no maximum.
+	MaxRX1DataRate *types.DataRate
+	// DutyCycleOverrides replace the duty cycle limits (0-1) of sub-bands, by
+	// the name of the sub-band, for gateways at a site that is granted a
+	// different allowance
+	DutyCycleOverrides map[string]float64
+	// DutyCycleGroup is the group of gateways this gateway shares its duty cycle with
+	DutyCycleGroup *DutyCycleGroup
+	// PowerErrorThreshold is the number of TX_POWER errors at the same TX
+	// power after which the maximum TX power of the gateway is lowered below
+	// that power. If it is 0, the maximum TX power is not learned.
+	PowerErrorThreshold int
+
+	timeSkew int64
+	gpsState int32
+
+	// timestampPrecision is the declared precision of the timestamps of the
+	// gateway (in µs), which depends on the model of the gateway
+	timestampPrecision uint32
+	gpsSync            gpsSync
+
+	attempts attempts
+	sent     sentDownlinks
+	txAcks   txAckHistory
+	airtime  airtimeLog
+	powerCap powerCap
+
+	// classQuotas keeps track of the downlink airtime of priority classes
+	classQuotas AirtimeWindow
+
 	token string
 
 	Monitors map[string]pb_monitor.GatewayClient
 
+	// Tracer records the spans of downlink attempts and bursts. If it is nil,
+	// no spans are recorded.
+	Tracer trace.Tracer
+
 	Ctx log.Interface
 }
 
@@ -49,15 +105,67 @@ func (g *Gateway) SetToken(token string) {
 	}
 }
 
+// tracer returns the Tracer of the gateway, or a tracer that does not record
+// any spans
+func (g *Gateway) tracer() trace.Tracer {
+	if g.Tracer == nil {
+		return trace.NewNoopTracerProvider().Tracer("")
+	}
+	return g.Tracer
+}
+
 func (g *Gateway) updateLastSeen() {
 	g.LastSeen = time.Now()
 }
 
+// TimeSkew returns the difference between the time of the gateway and the
+// time of the network, as calculated from the last status message. A positive
+// value means that the gateway clock is ahead.
+func (g *Gateway) TimeSkew() time.Duration {
+	return time.Duration(atomic.LoadInt64(&g.timeSkew))
+}
+
+func (g *Gateway) updateTimeSkew(status *pb.Status) {
+	if status.Time == 0 {
+		return
+	}
+	skew := time.Unix(0, status.Time).Sub(time.Now())
+	atomic.StoreInt64(&g.timeSkew, int64(skew))
+}
+
+// GPS states of a gateway
+const (
+	gpsUnknown int32 = iota
+	gpsLocked
+	gpsLost
+)
+
+// GPSLost returns true if the gateway was synchronized with GPS, but reported
+// in its last status that it lost its GPS lock
+func (g *Gateway) GPSLost() bool {
+	return atomic.LoadInt32(&g.gpsState) == gpsLost
+}
+
+func (g *Gateway) updateGPSState(status *pb.Status) {
+	if gps := status.GetGps(); gps != nil && gps.Time != 0 {
+		g.gpsSync.set(gps.Time, status.Timestamp)
+		if atomic.SwapInt32(&g.gpsState, gpsLocked) == gpsLost {
+			g.Ctx.Info("Gateway regained GPS lock")
+		}
+		return
+	}
+	if atomic.CompareAndSwapInt32(&g.gpsState, gpsLocked, gpsLost) {
+		g.Ctx.Warn("Gateway lost GPS lock")
+	}
+}
+
 func (g *Gateway) HandleStatus(status *pb.Status) (err error) {
 	if err = g.Status.Update(status); err != nil {
 		return err
 	}
 	g.updateLastSeen()
+	g.updateTimeSkew(status)
+	g.updateGPSState(status)
 
 	if g.Monitors != nil {
 		for _, monitor := range g.Monitors {
@@ -67,6 +175,20 @@ func (g *Gateway) HandleStatus(status *pb.Status) (err error) {
 	return nil
 }
 
+// SetTimestampPrecision sets the declared precision of the timestamps of the
+// gateway (in µs). The guard between transmissions scales with at least this
+// precision.
+func (g *Gateway) SetTimestampPrecision(precision uint32) {
+	atomic.StoreUint32(&g.timestampPrecision, precision)
+	g.Schedule.SetTimestampPrecision(time.Duration(precision) * time.Microsecond)
+}
+
+// TimestampPrecision returns the declared precision of the timestamps of the
+// gateway (in µs)
+func (g *Gateway) TimestampPrecision() uint32 {
+	return atomic.LoadUint32(&g.timestampPrecision)
+}
+
 func (g *Gateway) HandleUplink(uplink *pb_router.UplinkMessage) (err error) {
 	if err = g.Utilization.AddRx(uplink); err != nil {
 		return err
@@ -90,16 +212,31 @@ func (g *Gateway) HandleUplink(uplink *pb_router.UplinkMessage) (err error) {
 }
 
 func (g *Gateway) HandleDownlink(identifier string, downlink *pb_router.DownlinkMessage) (err error) {
-	ctx := g.Ctx.WithField("Identifier", identifier)
+	ctx := g.Ctx.WithFields(log.Fields{
+		"Identifier": identifier,
+		"TraceID":    downlink.TraceId,
+	})
 	if err = g.Schedule.Schedule(identifier, downlink); err != nil {
 		ctx.WithError(err).Warn("Could not schedule downlink")
 		return err
 	}
+	g.handleScheduled(identifier, downlink)
+	return nil
+}
+
+// handleScheduled handles a downlink that was scheduled in the schedule
+func (g *Gateway) handleScheduled(identifier string, downlink *pb_router.DownlinkMessage) {
+	if config := downlink.GatewayConfiguration; config != nil && g.PowerErrorThreshold > 0 {
+		g.powerCap.request(identifier, config.Power)
+	}
 
 	if g.Monitors != nil {
 		for _, monitor := range g.Monitors {
 			go monitor.SendDownlink(downlink)
 		}
 	}
-	return nil
+	g.Ctx.WithFields(log.Fields{
+		"Identifier": identifier,
+		"TraceID":    downlink.TraceId,
+	}).Debug("Scheduled downlink")
 }
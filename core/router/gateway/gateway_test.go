@@ -5,7 +5,9 @@ package gateway
 
 import (
 	"testing"
+	"time"
 
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
 	. "github.com/smartystreets/assertions"
 )
@@ -15,3 +17,57 @@ func TestNewGateway(t *testing.T) {
 	gtw := NewGateway(GetLogger(t, "TestNewGateway"), "eui-0102030405060708")
 	a.So(gtw, ShouldNotBeNil)
 }
+
+func TestGatewayTimeSkew(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayTimeSkew"), "eui-0102030405060708")
+	a.So(gtw.TimeSkew(), ShouldEqual, 0)
+
+	// Status without time does not change the skew
+	gtw.HandleStatus(&pb_gateway.Status{})
+	a.So(gtw.TimeSkew(), ShouldEqual, 0)
+
+	// Gateway clock is 2 seconds ahead
+	gtw.HandleStatus(&pb_gateway.Status{Time: time.Now().Add(2 * time.Second).UnixNano()})
+	a.So(gtw.TimeSkew(), ShouldAlmostEqual, 2*time.Second, almostEqual)
+
+	// Gateway clock is 500 milliseconds behind
+	gtw.HandleStatus(&pb_gateway.Status{Time: time.Now().Add(-500 * time.Millisecond).UnixNano()})
+	a.So(gtw.TimeSkew(), ShouldAlmostEqual, -500*time.Millisecond, almostEqual)
+}
+
+func TestGatewayGPSLost(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayGPSLost"), "eui-0102030405060708")
+
+	// A gateway that never had GPS did not lose it
+	gtw.HandleStatus(&pb_gateway.Status{})
+	a.So(gtw.GPSLost(), ShouldBeFalse)
+
+	gtw.HandleStatus(&pb_gateway.Status{Gps: &pb_gateway.GPSMetadata{Time: time.Now().UnixNano()}})
+	a.So(gtw.GPSLost(), ShouldBeFalse)
+
+	gtw.HandleStatus(&pb_gateway.Status{Gps: &pb_gateway.GPSMetadata{Latitude: 52.37, Longitude: 4.89}})
+	a.So(gtw.GPSLost(), ShouldBeTrue)
+
+	gtw.HandleStatus(&pb_gateway.Status{Gps: &pb_gateway.GPSMetadata{Time: time.Now().UnixNano()}})
+	a.So(gtw.GPSLost(), ShouldBeFalse)
+}
+
+func TestGatewayNextBeacon(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayNextBeacon"), "eui-0102030405060708")
+
+	// The gateway was never synchronized with GPS
+	_, _, ok := gtw.NextBeacon(time.Now())
+	a.So(ok, ShouldBeFalse)
+
+	// The gateway is synchronized with GPS exactly at a beacon
+	synced := gpsEpoch.Add(1000*BeaconPeriod - gpsLeapSeconds)
+	gtw.HandleStatus(&pb_gateway.Status{Timestamp: 5000000, Gps: &pb_gateway.GPSMetadata{Time: synced.UnixNano()}})
+
+	beaconTime, timestamp, ok := gtw.NextBeacon(synced.Add(time.Second))
+	a.So(ok, ShouldBeTrue)
+	a.So(beaconTime, ShouldEqual, 1001*128)
+	a.So(timestamp, ShouldEqual, 5000000+128000000)
+}
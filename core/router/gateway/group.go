@@ -0,0 +1,50 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import "sync"
+
+// DutyCycleGroup is a group of gateways that share their duty cycle, for
+// example because they are co-located and share the same spectrum
+type DutyCycleGroup struct {
+	sync.RWMutex
+	Name     string
+	gateways []*Gateway
+}
+
+// NewDutyCycleGroup creates a new, empty DutyCycleGroup
+func NewDutyCycleGroup(name string) *DutyCycleGroup {
+	return &DutyCycleGroup{Name: name}
+}
+
+// Add adds the gateway to the group
+func (g *DutyCycleGroup) Add(gtw *Gateway) {
+	g.Lock()
+	defer g.Unlock()
+	g.gateways = append(g.gateways, gtw)
+	gtw.DutyCycleGroup = g
+}
+
+// ChannelTx returns the sum of the tx utilization of all gateways in the
+// group for the given channel
+func (g *DutyCycleGroup) ChannelTx(frequency uint64) (tx float64) {
+	g.RLock()
+	defer g.RUnlock()
+	for _, gtw := range g.gateways {
+		_, gtwTx := gtw.Utilization.GetChannel(frequency)
+		tx += gtwTx
+	}
+	return
+}
+
+// ChannelTx returns the tx utilization for the given channel that counts
+// against the duty cycle of the gateway. If the gateway is part of a duty
+// cycle group, this is the tx utilization of the entire group.
+func (g *Gateway) ChannelTx(frequency uint64) float64 {
+	if g.DutyCycleGroup != nil {
+		return g.DutyCycleGroup.ChannelTx(frequency)
+	}
+	_, tx := g.Utilization.GetChannel(frequency)
+	return tx
+}
@@ -0,0 +1,38 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"testing"
+
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestDutyCycleGroup(t *testing.T) {
+	a := New(t)
+
+	gtwA := NewGateway(GetLogger(t, "TestDutyCycleGroup"), "eui-0102030405060708")
+	gtwB := NewGateway(GetLogger(t, "TestDutyCycleGroup"), "eui-0807060504030201")
+	gtwC := NewGateway(GetLogger(t, "TestDutyCycleGroup"), "eui-0101010101010101")
+
+	group := NewDutyCycleGroup("site-1")
+	group.Add(gtwA)
+	group.Add(gtwB)
+
+	gtwA.Utilization.AddTx(buildDownlink(8680000000))
+	gtwA.Utilization.Tick()
+
+	// Airtime of gateway A counts against gateway B
+	a.So(gtwA.ChannelTx(8680000000), ShouldAlmostEqual, 0.041216/5.0)
+	a.So(gtwB.ChannelTx(8680000000), ShouldAlmostEqual, 0.041216/5.0)
+
+	// But not against gateways outside the group
+	a.So(gtwC.ChannelTx(8680000000), ShouldEqual, 0)
+
+	// Airtime of both gateways is summed
+	gtwB.Utilization.AddTx(buildDownlink(8680000000))
+	gtwB.Utilization.Tick()
+	a.So(gtwA.ChannelTx(8680000000), ShouldAlmostEqual, 0.082432/5.0)
+}
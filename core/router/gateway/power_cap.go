@@ -0,0 +1,112 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"sync"
+	"time"
+
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+)
+
+// powerRequestHistorySize is the number of scheduled downlinks for which the
+// requested TX power is kept per gateway
+const powerRequestHistorySize = 64
+
+// PowerCapRetention is the time that the maximum TX power that was learned
+// from TX_POWER errors is kept. After that, the gateway can use its full TX
+// power again, until it returns TX_POWER errors again.
+var PowerCapRetention = 24 * time.Hour
+
+// powerCap learns the maximum TX power of a gateway from the TX_POWER errors
+// it returns for downlinks
+type powerCap struct {
+	sync.Mutex
+	requested map[string]int32
+	order     []string
+	errors    map[int32]int
+	max       int32
+	capped    bool
+	cappedAt  time.Time
+}
+
+func (c *powerCap) request(identifier string, power int32) {
+	c.Lock()
+	defer c.Unlock()
+	if c.requested == nil {
+		c.requested = make(map[string]int32)
+	}
+	if _, ok := c.requested[identifier]; !ok {
+		c.order = append(c.order, identifier)
+	}
+	c.requested[identifier] = power
+	if len(c.order) > powerRequestHistorySize {
+		delete(c.requested, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// powerError handles a TX_POWER error for the downlink with the given
+// identifier. It returns the new maximum TX power if it was lowered.
+func (c *powerCap) powerError(identifier string, threshold int) (max int32, lowered bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.expire()
+	power, ok := c.requested[identifier]
+	if !ok {
+		return 0, false
+	}
+	if c.errors == nil {
+		c.errors = make(map[int32]int)
+	}
+	c.errors[power]++
+	if c.errors[power] < threshold {
+		return 0, false
+	}
+	if c.capped && c.max < power {
+		return 0, false
+	}
+	c.max, c.capped, c.cappedAt = power-1, true, time.Now()
+	return c.max, true
+}
+
+// expire forgets the maximum TX power and the errors it was learned from after
+// PowerCapRetention. It should be called with the lock held.
+func (c *powerCap) expire() {
+	if c.capped && time.Since(c.cappedAt) > PowerCapRetention {
+		c.max, c.capped, c.errors = 0, false, nil
+	}
+}
+
+func (c *powerCap) get() (max int32, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.expire()
+	return c.max, c.capped
+}
+
+// isPowerError returns true if the gateway did not transmit the downlink
+// because it does not support the requested TX power
+func isPowerError(err error) bool {
+	txErr, ok := err.(*pb_router.TxError)
+	return ok && txErr.Reason == pb_router.TxAcknowledgment_TX_POWER
+}
+
+// handlePowerError lowers the maximum TX power of the gateway if it returned
+// PowerErrorThreshold TX_POWER errors for downlinks at the same power
+func (g *Gateway) handlePowerError(identifier string) {
+	if g.PowerErrorThreshold <= 0 {
+		return
+	}
+	if max, lowered := g.powerCap.powerError(identifier, g.PowerErrorThreshold); lowered {
+		g.Ctx.WithField("MaxTXPower", max).Warn("Lowered maximum TX power after TX power errors")
+	}
+}
+
+// MaxTXPower returns the maximum conducted TX power (in dBm) that was learned
+// from the TX_POWER errors of the gateway in the last PowerCapRetention. If ok
+// is false, there is no maximum.
+func (g *Gateway) MaxTXPower() (power int32, ok bool) {
+	return g.powerCap.get()
+}
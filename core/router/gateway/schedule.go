@@ -22,23 +22,44 @@ type Schedule interface {
 	fmt.GoStringer
 	// Synchronize the schedule with the gateway timestamp (in microseconds)
 	Sync(timestamp uint32)
+	// Get the measured precision of the gateway timestamps
+	Precision() time.Duration
+	// Set the factor by which the guard between transmissions scales with the precision of the gateway timestamps
+	SetGuardFactor(factor float64)
+	// Set the declared precision of the gateway timestamps, the guard is at least this precision, regardless of the guard factor
+	SetTimestampPrecision(precision time.Duration)
 	// Get an "option" on a transmission slot at timestamp for the maximum duration of length (both in microseconds)
 	GetOption(timestamp uint32, length uint32) (id string, score uint)
 	// Schedule a transmission on a slot
 	Schedule(id string, downlink *router_pb.DownlinkMessage) error
+	// Schedule a burst of transmissions at the timestamps of the downlinks, for the given lengths (in microseconds), only if none of them conflicts with a scheduled transmission
+	ScheduleBurst(downlinks []*router_pb.DownlinkMessage, lengths []uint32) (ids []string, err error)
+	// Cancel a transmission on a slot. Returns false if there was no transmission to cancel
+	Cancel(id string) bool
+	// Get the identifiers of the options and transmissions that were not sent yet
+	Pending() []string
+	// Get the transmissions that were scheduled but not sent yet
+	PendingDownlinks() []*router_pb.DownlinkMessage
 	// Subscribe to downlink messages
 	Subscribe(subscriptionID string) <-chan *router_pb.DownlinkMessage
 	// Whether the gateway has active downlink
 	IsActive() bool
+	// Get the number of conflicts of a downlink with the given timestamp and length (both in microseconds)
+	Conflicts(timestamp uint32, length uint32) uint
+	// Get the lead time of the last scheduled downlink and the maximum lead time.
+	// Both are zero until the schedule is synchronized with Sync.
+	LeadTime() (current time.Duration, max time.Duration)
 	// Stop the subscription
 	Stop(subscriptionID string)
+	// Get the number of subscribers to downlink messages
+	Subscribers() int
 }
 
 // NewSchedule creates a new Schedule
 func NewSchedule(ctx log.Interface) Schedule {
 	s := &schedule{
-		ctx:   ctx,
-		items: make(map[string]*scheduledItem),
+		ctx:                   ctx,
+		items:                 make(map[string]*scheduledItem),
 		downlinkSubscriptions: make(map[string]chan *router_pb.DownlinkMessage),
 	}
 	go func() {
@@ -69,6 +90,7 @@ type scheduledItem struct {
 	length     uint32
 	score      uint
 	payload    *router_pb.DownlinkMessage
+	cancelled  bool
 }
 
 type schedule struct {
@@ -79,6 +101,17 @@ type schedule struct {
 	downlink                  chan *router_pb.DownlinkMessage
 	downlinkSubscriptionsLock sync.RWMutex
 	downlinkSubscriptions     map[string]chan *router_pb.DownlinkMessage
+
+	// Lead time is the time between scheduling a downlink and its transmission
+	leadTime    time.Duration
+	maxLeadTime time.Duration
+
+	// precision is the moving average of the difference between consecutive
+	// synchronizations; the guard between transmissions is guardFactor times
+	// the precision, but at least the declared precision
+	precision         time.Duration
+	declaredPrecision time.Duration
+	guardFactor       float64
 }
 
 func (s *schedule) GoString() (str string) {
@@ -96,17 +129,35 @@ var Deadline = 400 * time.Millisecond
 
 const uintmax = 1 << 32
 
+// scheduledConflicts is the number of conflicts that a scheduled transmission
+// counts for; an option on a slot counts for one conflict
+const scheduledConflicts = 100
+
 // getConflicts walks over the schedule and returns the number of conflicts.
 // Both timestamp and length are in microseconds
 func (s *schedule) getConflicts(timestamp uint32, length uint32) (conflicts uint) {
 	s.RLock()
 	defer s.RUnlock()
+	return s.conflicts(timestamp, length)
+}
+
+// conflicts is like getConflicts, but should be called with the lock held
+func (s *schedule) conflicts(timestamp uint32, length uint32) (conflicts uint) {
+	precision := time.Duration(float64(s.precision) * s.guardFactor)
+	if s.declaredPrecision > precision {
+		precision = s.declaredPrecision
+	}
+	guard := uint64(precision / time.Microsecond)
 	for _, item := range s.items {
 		scheduledFrom := uint64(item.timestamp) % uintmax
 		scheduledTo := scheduledFrom + uint64(item.length)
 		from := uint64(timestamp)
 		to := from + uint64(length)
 
+		// Keep a guard between transmissions of gateways with imprecise timestamps
+		scheduledTo += guard
+		to += guard
+
 		if scheduledTo > uintmax || to > uintmax {
 			if scheduledTo-uintmax <= from || scheduledFrom >= to-uintmax {
 				continue
@@ -118,7 +169,7 @@ func (s *schedule) getConflicts(timestamp uint32, length uint32) (conflicts uint
 		if item.payload == nil {
 			conflicts++
 		} else {
-			conflicts += 100
+			conflicts += scheduledConflicts
 		}
 	}
 	return
@@ -136,9 +187,59 @@ func (s *schedule) realtime(timestamp uint32) (t time.Time) {
 	return
 }
 
+// see interface
+func (s *schedule) LeadTime() (current time.Duration, max time.Duration) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.leadTime, s.maxLeadTime
+}
+
 // see interface
 func (s *schedule) Sync(timestamp uint32) {
-	atomic.StoreInt64(&s.offset, time.Now().UnixNano()-int64(timestamp)*1000)
+	offset := time.Now().UnixNano() - int64(timestamp)*1000
+	if previous := atomic.SwapInt64(&s.offset, offset); previous != 0 {
+		s.updatePrecision(offset - previous)
+	}
+}
+
+// updatePrecision updates the moving average of the difference between
+// consecutive synchronizations. The difference is caused by jitter of the
+// gateway timestamps and of the backhaul.
+func (s *schedule) updatePrecision(drift int64) {
+	const rollover = int64(uintmax) * 1000
+	drift %= rollover
+	if drift > rollover/2 {
+		drift -= rollover
+	} else if drift < -rollover/2 {
+		drift += rollover
+	}
+	if drift < 0 {
+		drift = -drift
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.precision += (time.Duration(drift) - s.precision) / 8
+}
+
+// see interface
+func (s *schedule) Precision() time.Duration {
+	s.RLock()
+	defer s.RUnlock()
+	return s.precision
+}
+
+// see interface
+func (s *schedule) SetGuardFactor(factor float64) {
+	s.Lock()
+	defer s.Unlock()
+	s.guardFactor = factor
+}
+
+// see interface
+func (s *schedule) SetTimestampPrecision(precision time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	s.declaredPrecision = precision
 }
 
 // see interface
@@ -160,67 +261,146 @@ func (s *schedule) GetOption(timestamp uint32, length uint32) (id string, score
 
 // see interface
 func (s *schedule) Schedule(id string, downlink *router_pb.DownlinkMessage) error {
-	ctx := s.ctx.WithField("Identifier", id)
-
 	s.Lock()
 	defer s.Unlock()
 	if item, ok := s.items[id]; ok {
-		item.payload = downlink
-
-		if lorawan := downlink.GetProtocolConfiguration().GetLorawan(); lorawan != nil {
-			var time time.Duration
-			if lorawan.Modulation == pb_lorawan.Modulation_LORA {
-				// Calculate max ToA
-				time, _ = toa.ComputeLoRa(
-					uint(len(downlink.Payload)),
-					lorawan.DataRate,
-					lorawan.CodingRate,
-				)
-			}
-			if lorawan.Modulation == pb_lorawan.Modulation_FSK {
-				// Calculate max ToA
-				time, _ = toa.ComputeFSK(
-					uint(len(downlink.Payload)),
-					int(lorawan.BitRate),
-				)
-			}
-			item.length = uint32(time / 1000)
+		s.schedule(item, downlink)
+		return nil
+	}
+	return errors.NewErrNotFound(id)
+}
+
+// see interface
+func (s *schedule) ScheduleBurst(downlinks []*router_pb.DownlinkMessage, lengths []uint32) (ids []string, err error) {
+	s.Lock()
+	defer s.Unlock()
+	for i, downlink := range downlinks {
+		if s.conflicts(downlink.GatewayConfiguration.Timestamp, lengths[i]) >= scheduledConflicts {
+			return nil, errors.NewErrInvalidArgument("Downlink burst", fmt.Sprintf("frame %d conflicts with a scheduled transmission", i))
+		}
+	}
+	ids = make([]string, 0, len(downlinks))
+	for i, downlink := range downlinks {
+		timestamp := downlink.GatewayConfiguration.Timestamp
+		item := &scheduledItem{
+			id:         random.String(32),
+			deadlineAt: s.realtime(timestamp).Add(-1 * Deadline),
+			timestamp:  timestamp,
+			length:     lengths[i],
 		}
+		s.items[item.id] = item
+		s.schedule(item, downlink)
+		ids = append(ids, item.id)
+	}
+	return ids, nil
+}
+
+// schedule the transmission of the item. It should be called with the lock
+// held.
+func (s *schedule) schedule(item *scheduledItem, downlink *router_pb.DownlinkMessage) {
+	ctx := s.ctx.WithField("Identifier", item.id)
+	item.payload = downlink
 
-		if time.Now().Before(item.deadlineAt) {
-			// Schedule transmission before the Deadline
-			go func() {
-				waitTime := item.deadlineAt.Sub(time.Now())
-				ctx.WithField("Remaining", waitTime).Info("Scheduled downlink")
-				<-time.After(waitTime)
-				s.RLock()
-				defer s.RUnlock()
-				if s.downlink != nil {
+	// The lead time is meaningless until the schedule is synchronized
+	if atomic.LoadInt64(&s.offset) != 0 {
+		s.leadTime = s.realtime(item.timestamp).Sub(time.Now())
+		if s.leadTime > s.maxLeadTime {
+			s.maxLeadTime = s.leadTime
+		}
+	}
+
+	if lorawan := downlink.GetProtocolConfiguration().GetLorawan(); lorawan != nil {
+		var time time.Duration
+		if lorawan.Modulation == pb_lorawan.Modulation_LORA {
+			// Calculate max ToA
+			time, _ = toa.ComputeLoRa(
+				uint(len(downlink.Payload)),
+				lorawan.DataRate,
+				lorawan.CodingRate,
+			)
+		}
+		if lorawan.Modulation == pb_lorawan.Modulation_FSK {
+			// Calculate max ToA
+			time, _ = toa.ComputeFSK(
+				uint(len(downlink.Payload)),
+				int(lorawan.BitRate),
+			)
+		}
+		item.length = uint32(time / 1000)
+	}
+
+	if time.Now().Before(item.deadlineAt) {
+		// Schedule transmission before the Deadline
+		go func() {
+			waitTime := item.deadlineAt.Sub(time.Now())
+			ctx.WithField("Remaining", waitTime).Info("Scheduled downlink")
+			<-time.After(waitTime)
+			s.RLock()
+			defer s.RUnlock()
+			if s.downlink != nil && !item.cancelled {
+				s.downlink <- item.payload
+			}
+		}()
+	} else {
+		go func() {
+			s.RLock()
+			defer s.RUnlock()
+			if item.cancelled {
+				return
+			}
+			if s.downlink != nil {
+				overdue := time.Now().Sub(item.deadlineAt)
+				if overdue < Deadline {
+					// Immediately send it
+					ctx.WithField("Overdue", overdue).Warn("Send Late Downlink")
 					s.downlink <- item.payload
-				}
-			}()
-		} else {
-			go func() {
-				s.RLock()
-				defer s.RUnlock()
-				if s.downlink != nil {
-					overdue := time.Now().Sub(item.deadlineAt)
-					if overdue < Deadline {
-						// Immediately send it
-						ctx.WithField("Overdue", overdue).Warn("Send Late Downlink")
-						s.downlink <- item.payload
-					} else {
-						ctx.WithField("Overdue", overdue).Warn("Discard Late Downlink")
-					}
 				} else {
-					ctx.Warn("Unable to send Downlink")
+					ctx.WithField("Overdue", overdue).Warn("Discard Late Downlink")
 				}
-			}()
+			} else {
+				ctx.Warn("Unable to send Downlink")
+			}
+		}()
+	}
+}
+
+// see interface
+func (s *schedule) Cancel(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+	item, ok := s.items[id]
+	if !ok || item.cancelled {
+		return false
+	}
+	item.cancelled = true
+	delete(s.items, id)
+	return true
+}
+
+// see interface
+func (s *schedule) Pending() (ids []string) {
+	s.RLock()
+	defer s.RUnlock()
+	now := time.Now()
+	for id, item := range s.items {
+		if !item.cancelled && now.Before(item.deadlineAt) {
+			ids = append(ids, id)
 		}
+	}
+	return
+}
 
-		return nil
+// see interface
+func (s *schedule) PendingDownlinks() (downlinks []*router_pb.DownlinkMessage) {
+	s.RLock()
+	defer s.RUnlock()
+	now := time.Now()
+	for _, item := range s.items {
+		if item.payload != nil && !item.cancelled && now.Before(item.deadlineAt) {
+			downlinks = append(downlinks, item.payload)
+		}
 	}
-	return errors.NewErrNotFound(id)
+	return
 }
 
 func (s *schedule) Stop(subscriptionID string) {
@@ -269,6 +449,19 @@ func (s *schedule) Subscribe(subscriptionID string) <-chan *router_pb.DownlinkMe
 	return sub
 }
 
+// see interface
+func (s *schedule) Subscribers() int {
+	s.downlinkSubscriptionsLock.RLock()
+	defer s.downlinkSubscriptionsLock.RUnlock()
+	return len(s.downlinkSubscriptions)
+}
+
+// see interface
+func (s *schedule) Conflicts(timestamp uint32, length uint32) uint {
+	return s.getConflicts(timestamp, length)
+}
+
+// see interface
 func (s *schedule) IsActive() bool {
 	s.RLock()
 	defer s.RUnlock()
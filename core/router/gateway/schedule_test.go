@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
 	router_pb "github.com/TheThingsNetwork/ttn/api/router"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
 	. "github.com/smartystreets/assertions"
@@ -118,6 +119,38 @@ func TestScheduleSchedule(t *testing.T) {
 	a.So(conflicts, ShouldEqual, 100)
 }
 
+func TestScheduleScheduleBurst(t *testing.T) {
+	a := New(t)
+	s := NewSchedule(GetLogger(t, "TestScheduleScheduleBurst")).(*schedule)
+
+	s.Sync(0)
+
+	burst := func(timestamps ...uint32) (downlinks []*router_pb.DownlinkMessage, lengths []uint32) {
+		for _, timestamp := range timestamps {
+			downlinks = append(downlinks, &router_pb.DownlinkMessage{
+				GatewayConfiguration: &pb_gateway.TxConfiguration{Timestamp: timestamp},
+			})
+			lengths = append(lengths, 100)
+		}
+		return
+	}
+
+	id, _ := s.GetOption(10000000, 100)
+	err := s.Schedule(id, &router_pb.DownlinkMessage{})
+	a.So(err, ShouldBeNil)
+
+	// None of the frames is scheduled if one of them conflicts
+	_, err = s.ScheduleBurst(burst(9000000, 10000000))
+	a.So(err, ShouldNotBeNil)
+	a.So(s.Conflicts(9000000, 100), ShouldEqual, 0)
+
+	ids, err := s.ScheduleBurst(burst(9000000, 11000000))
+	a.So(err, ShouldBeNil)
+	a.So(ids, ShouldHaveLength, 2)
+	a.So(s.Conflicts(9000000, 100), ShouldEqual, 100)
+	a.So(s.Conflicts(11000000, 100), ShouldEqual, 100)
+}
+
 func TestScheduleSubscribe(t *testing.T) {
 	a := New(t)
 	s := NewSchedule(GetLogger(t, "TestScheduleSubscribe")).(*schedule)
@@ -158,3 +191,63 @@ func TestScheduleSubscribe(t *testing.T) {
 	<-time.After(500 * time.Millisecond)
 
 }
+
+func TestScheduleLeadTime(t *testing.T) {
+	a := New(t)
+	s := NewSchedule(GetLogger(t, "TestScheduleLeadTime")).(*schedule)
+
+	// Before the first Sync, the lead time is not known
+	id, _ := s.GetOption(1000000, 100)
+	a.So(s.Schedule(id, &router_pb.DownlinkMessage{}), ShouldBeNil)
+	current, max := s.LeadTime()
+	a.So(current, ShouldEqual, 0)
+	a.So(max, ShouldEqual, 0)
+
+	s.Sync(0)
+
+	for _, timestamp := range []uint32{1000000, 3000000, 2000000} {
+		id, _ := s.GetOption(timestamp, 100)
+		err := s.Schedule(id, &router_pb.DownlinkMessage{})
+		a.So(err, ShouldBeNil)
+	}
+
+	current, max = s.LeadTime()
+	a.So(current, ShouldAlmostEqual, 2*time.Second, almostEqual)
+	a.So(max, ShouldAlmostEqual, 3*time.Second, almostEqual)
+}
+
+func TestScheduleJitterGuard(t *testing.T) {
+	a := New(t)
+	precise := NewSchedule(GetLogger(t, "TestScheduleJitterGuard")).(*schedule)
+	imprecise := NewSchedule(GetLogger(t, "TestScheduleJitterGuard")).(*schedule)
+
+	// The timestamps of the imprecise gateway jitter 20ms
+	for i := 0; i < 20; i++ {
+		precise.Sync(1000)
+		imprecise.Sync(1000 + uint32(i%2)*20000)
+	}
+	a.So(precise.Precision(), ShouldBeLessThan, time.Millisecond)
+	a.So(imprecise.Precision(), ShouldBeGreaterThan, 10*time.Millisecond)
+
+	for _, s := range []*schedule{precise, imprecise} {
+		s.SetGuardFactor(1)
+		s.GetOption(1000000, 100000)
+	}
+
+	// A transmission 5ms after the scheduled one only conflicts on the imprecise gateway
+	a.So(precise.getConflicts(1105000, 100000), ShouldEqual, 0)
+	a.So(imprecise.getConflicts(1105000, 100000), ShouldEqual, 1)
+
+	// Without guard factor, both gateways only conflict on overlap
+	imprecise.SetGuardFactor(0)
+	a.So(imprecise.getConflicts(1105000, 100000), ShouldEqual, 0)
+
+	// The declared precision is the minimum guard, even without guard factor
+	imprecise.SetTimestampPrecision(10 * time.Millisecond)
+	a.So(imprecise.getConflicts(1105000, 100000), ShouldEqual, 1)
+	a.So(imprecise.getConflicts(1110000, 100000), ShouldEqual, 0)
+
+	// A larger guard factor is not lowered to the declared precision
+	imprecise.SetGuardFactor(1)
+	a.So(imprecise.getConflicts(1110000, 100000), ShouldEqual, 1)
+}
@@ -0,0 +1,43 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"math"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/apex/log"
+)
+
+// ErrTXPowerBelowMinimum is returned if the maximum TX power that was learned
+// from TX_POWER errors is lower than the minimum TX power of the gateway
+var ErrTXPowerBelowMinimum = errors.New("Maximum TX power of gateway is below its minimum TX power")
+
+// TXPower returns the conducted TX power (in dBm) the gateway should use in
+// order to radiate the given EIRP (in dBm), taking into account the gain of the
+// antenna and the loss of the cable. If the result is lower than the minimum TX
+// power of the gateway, the minimum TX power is returned. The result never
+// exceeds the maximum TX power that was learned from TX_POWER errors; if that
+// maximum is lower than the minimum TX power, ErrTXPowerBelowMinimum is
+// returned.
+func (g *Gateway) TXPower(eirp int32) (int32, error) {
+	power := int32(math.Floor(float64(eirp) - g.AntennaGain + g.CableLoss))
+	if max, ok := g.MaxTXPower(); ok {
+		if max < g.MinTXPower {
+			return 0, ErrTXPowerBelowMinimum
+		}
+		if power > max {
+			power = max
+		}
+	}
+	if power < g.MinTXPower {
+		g.Ctx.WithFields(log.Fields{
+			"EIRP":       eirp,
+			"Power":      power,
+			"MinTXPower": g.MinTXPower,
+		}).Debug("Clamped TX power to minimum")
+		return g.MinTXPower, nil
+	}
+	return power, nil
+}
@@ -0,0 +1,48 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import "sync"
+
+// txAckHistorySize is the number of recent TxAcks that is kept per gateway
+const txAckHistorySize = 20
+
+// txAckHistory keeps track of the results of the most recent TxAcks
+type txAckHistory struct {
+	sync.RWMutex
+	results [txAckHistorySize]bool
+	length  int
+	next    int
+}
+
+func (h *txAckHistory) add(success bool) {
+	h.Lock()
+	defer h.Unlock()
+	h.results[h.next] = success
+	h.next = (h.next + 1) % txAckHistorySize
+	if h.length < txAckHistorySize {
+		h.length++
+	}
+}
+
+func (h *txAckHistory) successRate() (rate float64, ok bool) {
+	h.RLock()
+	defer h.RUnlock()
+	if h.length == 0 {
+		return 0, false
+	}
+	var successes int
+	for _, success := range h.results[:h.length] {
+		if success {
+			successes++
+		}
+	}
+	return float64(successes) / float64(h.length), true
+}
+
+// TxAckSuccessRate returns the fraction of recent downlinks that the gateway
+// acknowledged as transmitted. If ok is false, there are no recent TxAcks.
+func (g *Gateway) TxAckSuccessRate() (rate float64, ok bool) {
+	return g.txAcks.successRate()
+}
@@ -22,6 +22,8 @@ type Utilization interface {
 	AddRx(uplink *pb_router.UplinkMessage) error
 	// AddRx updates the utilization for transmitting a downlink message
 	AddTx(downlink *pb_router.DownlinkMessage) error
+	// RemoveTx reverts AddTx for a downlink message that was not transmitted
+	RemoveTx(downlink *pb_router.DownlinkMessage) error
 	// Get returns the overall rx and tx utilization for the gateway. If the gateway has multiple channels, the values will be 0 <= value < numChannels
 	Get() (rx float64, tx float64)
 	// GetChannel returns the rx and tx utilization for the given channel. The values will be 0 <= value < 1
@@ -95,6 +97,14 @@ func (u *utilization) AddRx(uplink *pb_router.UplinkMessage) error {
 }
 
 func (u *utilization) AddTx(downlink *pb_router.DownlinkMessage) error {
+	return u.updateTx(downlink, 1)
+}
+
+func (u *utilization) RemoveTx(downlink *pb_router.DownlinkMessage) error {
+	return u.updateTx(downlink, -1)
+}
+
+func (u *utilization) updateTx(downlink *pb_router.DownlinkMessage, sign int64) error {
 	var t time.Duration
 	var err error
 	if lorawan := downlink.ProtocolConfiguration.GetLorawan(); lorawan != nil {
@@ -114,14 +124,14 @@ func (u *utilization) AddTx(downlink *pb_router.DownlinkMessage) error {
 	if t == 0 {
 		return nil
 	}
-	u.overallTx.Update(int64(t) / 1000)
+	u.overallTx.Update(sign * int64(t) / 1000)
 	frequency := downlink.GatewayConfiguration.Frequency
 	u.channelTxLock.Lock()
 	defer u.channelTxLock.Unlock()
 	if _, ok := u.channelTx[frequency]; !ok {
 		u.channelTx[frequency] = metrics.NewEWMA1()
 	}
-	u.channelTx[frequency].Update(int64(t) / 1000)
+	u.channelTx[frequency].Update(sign * int64(t) / 1000)
 	return nil
 }
 
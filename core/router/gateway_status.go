@@ -21,5 +21,9 @@ func (r *router) HandleGatewayStatus(gatewayID string, status *pb_gateway.Status
 	}()
 	r.status.gatewayStatus.Mark(1)
 	status.Router = r.Identity.Id
-	return r.getGateway(gatewayID).HandleStatus(status)
+	gateway := r.getGateway(gatewayID)
+	if precision, ok := r.timestampPrecisions[status.Platform]; ok && len(precision) > 0 {
+		gateway.SetTimestampPrecision(uint32(precision[0]))
+	}
+	return gateway.HandleStatus(status)
 }
@@ -8,6 +8,7 @@ import (
 
 	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
 	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/core/component"
 	"github.com/TheThingsNetwork/ttn/core/router/gateway"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
@@ -38,3 +39,43 @@ func TestHandleGatewayStatus(t *testing.T) {
 	a.So(status, ShouldNotBeNil)
 	a.So(*status, ShouldResemble, *statusMessage)
 }
+
+func TestHandleGatewayStatusTimestampPrecision(t *testing.T) {
+	a := New(t)
+
+	router := &router{
+		Component: &component.Component{
+			Ctx:      GetLogger(t, "TestHandleGatewayStatusTimestampPrecision"),
+			Identity: &pb_discovery.Announcement{},
+		},
+		gateways: map[string]*gateway.Gateway{},
+		timestampPrecisions: map[string][]int{
+			"Precise":   {1000},
+			"Imprecise": {5000},
+		},
+	}
+	router.InitStatus()
+
+	for _, gtw := range []struct {
+		ID        string
+		Platform  string
+		Precision uint32
+		Conflicts uint
+	}{
+		{"eui-0102030405060708", "Precise", 1000, 0},
+		{"eui-0807060504030201", "Imprecise", 5000, 100},
+	} {
+		err := router.HandleGatewayStatus(gtw.ID, &pb_gateway.Status{Platform: gtw.Platform})
+		a.So(err, ShouldBeNil)
+
+		gateway := router.getGateway(gtw.ID)
+		a.So(gateway.TimestampPrecision(), ShouldEqual, gtw.Precision)
+
+		id, _ := gateway.Schedule.GetOption(1000000, 10000)
+		a.So(gateway.Schedule.Schedule(id, &pb.DownlinkMessage{}), ShouldBeNil)
+
+		// Without guard factor, the guard is the declared precision: 1ms for
+		// the precise and 5ms for the imprecise gateway
+		a.So(gateway.Schedule.Conflicts(1012000, 1000), ShouldEqual, gtw.Conflicts)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"math"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// DeviceTXPower is the TX power (in dBm) that devices are assumed to use for uplink
+const DeviceTXPower = 14.0
+
+// noiseFigure is the noise figure (in dB) of a typical LoRa receiver
+const noiseFigure = 6.0
+
+// requiredSNR contains the SNR (in dB) that is required to demodulate LoRa
+// transmissions for each spreading factor
+var requiredSNR = map[uint]float64{
+	7:  -7.5,
+	8:  -10,
+	9:  -12.5,
+	10: -15,
+	11: -17.5,
+	12: -20,
+}
+
+// LinkBudgetMargin estimates the margin (in dB) of the link budget of a
+// downlink option, based on the RSSI and SNR of the uplink, the SNR that is
+// required for the data rate of the downlink and the difference between the TX
+// power of the gateway and the TX power of the device. It returns 0 if the
+// margin can not be estimated.
+func LinkBudgetMargin(uplink *pb.UplinkMessage, option *pb_broker.DownlinkOption) float64 {
+	lorawan := option.GetProtocolConfig().GetLorawan()
+	if lorawan == nil || lorawan.Modulation != pb_lorawan.Modulation_LORA || option.GatewayConfig == nil {
+		return 0
+	}
+	dataRate, err := types.ParseDataRate(lorawan.DataRate)
+	if err != nil {
+		return 0
+	}
+	snr, ok := requiredSNR[dataRate.SpreadingFactor]
+	if !ok {
+		return 0
+	}
+
+	asymmetry := float64(option.GatewayConfig.Power) - DeviceTXPower
+
+	// The sensitivity of the device depends on the bandwidth of the downlink
+	sensitivity := -174 + 10*math.Log10(float64(dataRate.Bandwidth)*1000) + noiseFigure + snr
+	rssiMargin := float64(uplink.GatewayMetadata.Rssi) + asymmetry - sensitivity
+	snrMargin := float64(uplink.GatewayMetadata.Snr) + asymmetry - snr
+
+	return math.Min(rssiMargin, snrMargin)
+}
@@ -0,0 +1,45 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestLinkBudgetMargin(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+	up := newReferenceUplink()
+	up.GatewayMetadata.Rssi = -110
+	up.GatewayMetadata.Snr = -5
+	options := r.buildDownlinkOptions(up, false, newReferenceGateway(t, "EU_863_870"))
+	rx1, rx2 := options[1], options[0]
+
+	// SF7, 14 dBm: limited by the SNR: -5 + (14 - 14) - -7.5
+	a.So(LinkBudgetMargin(up, rx1), ShouldAlmostEqual, 2.5)
+
+	// SF9, 27 dBm: more margin because of the data rate and the TX power
+	a.So(LinkBudgetMargin(up, rx2), ShouldBeGreaterThan, LinkBudgetMargin(up, rx1))
+
+	// Lower TX power -> less margin
+	margin := LinkBudgetMargin(up, rx1)
+	rx1.GatewayConfig.Power = 10
+	a.So(LinkBudgetMargin(up, rx1), ShouldAlmostEqual, margin-4)
+
+	// Slower data rate -> more margin
+	margin = LinkBudgetMargin(up, rx1)
+	rx1.ProtocolConfig.GetLorawan().DataRate = "SF8BW125"
+	a.So(LinkBudgetMargin(up, rx1), ShouldAlmostEqual, margin+2.5)
+	a.So(margin, ShouldBeLessThan, LinkBudgetMargin(up, rx1))
+
+	// Weak uplink with a good SNR is limited by the sensitivity of the device
+	up.GatewayMetadata.Rssi = -120
+	up.GatewayMetadata.Snr = 10
+	rx1.ProtocolConfig.GetLorawan().DataRate = "SF7BW125"
+	rx1.GatewayConfig.Power = 14
+	a.So(LinkBudgetMargin(up, rx1), ShouldAlmostEqual, -120-(-174+51+noiseFigure-7.5), 0.1)
+}
@@ -8,6 +8,7 @@ import (
 
 	pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/golang/protobuf/ptypes/empty"
 	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
 	"google.golang.org/grpc"
 )
@@ -34,9 +35,12 @@ func (r *routerManager) GatewayStatus(ctx context.Context, in *pb.GatewayStatusR
 	if err != nil {
 		return nil, err
 	}
+	leadTime, maxLeadTime := gtw.Schedule.LeadTime()
 	return &pb.GatewayStatusResponse{
-		LastSeen: gtw.LastSeen.UnixNano(),
-		Status:   status,
+		LastSeen:    gtw.LastSeen.UnixNano(),
+		Status:      status,
+		LeadTime:    leadTime.Nanoseconds(),
+		MaxLeadTime: maxLeadTime.Nanoseconds(),
 	}, nil
 }
 
@@ -57,6 +61,43 @@ func (r *routerManager) GetStatus(ctx context.Context, in *pb.StatusRequest) (*p
 	return status, nil
 }
 
+func (r *routerManager) SetDownlinkSafeMode(ctx context.Context, in *pb.DownlinkSafeModeRequest) (*empty.Empty, error) {
+	claims, err := r.router.ValidateTTNAuthContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "No access")
+	}
+	if !claims.ComponentAccess(r.router.Identity.Id) {
+		return nil, errors.NewErrPermissionDenied(fmt.Sprintf("Claims do not grant access to %s", r.router.Identity.Id))
+	}
+	r.router.SetDownlinksDisabled(in.DownlinksDisabled)
+	return &empty.Empty{}, nil
+}
+
+func (r *routerManager) GetCapabilities(ctx context.Context, in *pb.CapabilitiesRequest) (*pb.Capabilities, error) {
+	if _, err := r.router.ValidateTTNAuthContext(ctx); err != nil {
+		return nil, errors.NewErrPermissionDenied("No access")
+	}
+	return r.router.Capabilities(), nil
+}
+
+func (r *routerManager) ClearGatewaySchedule(ctx context.Context, in *pb.ClearGatewayScheduleRequest) (*pb.ClearGatewayScheduleResponse, error) {
+	if in.GatewayId == "" {
+		return nil, errors.NewErrInvalidArgument("Clear Gateway Schedule Request", "ID is required")
+	}
+	claims, err := r.router.ValidateTTNAuthContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "No access")
+	}
+	if !claims.ComponentAccess(r.router.Identity.Id) {
+		return nil, errors.NewErrPermissionDenied(fmt.Sprintf("Claims do not grant access to %s", r.router.Identity.Id))
+	}
+	cleared, err := r.router.ClearSchedule(in.GatewayId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ClearGatewayScheduleResponse{Cleared: uint32(cleared)}, nil
+}
+
 // RegisterManager registers this router as a RouterManagerServer (github.com/TheThingsNetwork/ttn/api/router)
 func (r *router) RegisterManager(s *grpc.Server) {
 	server := &routerManager{r}
@@ -0,0 +1,140 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"strconv"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ErrQuotaExceeded is returned when a network exceeds its downlink airtime quota
+var ErrQuotaExceeded = errors.New("Downlink airtime quota exceeded")
+
+// quotaUsage returns the network and the airtime that the downlink is charged
+// for. It returns false if the downlink is not subject to the airtime quota.
+// MAC-only downlinks (FPort 0) are not subject to the airtime quota.
+func (r *router) quotaUsage(downlink *pb_broker.DownlinkMessage) (network string, airtime time.Duration, ok bool) {
+	if r.airtimeQuota == 0 {
+		return "", 0, false
+	}
+	if fPort, hasFPort := fPortFromPayload(downlink.Payload); hasFPort && fPort == 0 {
+		return "", 0, false
+	}
+	devAddr, ok := devAddrFromPayload(downlink.Payload)
+	if !ok {
+		return "", 0, false // We can only account for downlink to devices with a DevAddr
+	}
+	airtime = downlinkTimeOnAir(downlink)
+	if airtime == 0 {
+		return "", 0, false
+	}
+	return strconv.Itoa(int(devAddr[0] >> 1)), airtime, true // NwkID
+}
+
+// consumeQuota charges the airtime of the downlink to the network of the device
+func (r *router) consumeQuota(downlink *pb_broker.DownlinkMessage) error {
+	network, airtime, ok := r.quotaUsage(downlink)
+	if !ok {
+		return nil
+	}
+	if !r.quota.Consume(network, airtime, r.airtimeQuota, time.Now()) {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// refundQuota refunds the airtime that consumeQuota charged for the downlink
+func (r *router) refundQuota(downlink *pb_broker.DownlinkMessage) {
+	network, airtime, ok := r.quotaUsage(downlink)
+	if !ok {
+		return
+	}
+	r.quota.Refund(network, airtime)
+}
+
+// downlinkTimeOnAir returns the time on air of the downlink, or zero if it can
+// not be calculated
+func downlinkTimeOnAir(downlink *pb_broker.DownlinkMessage) time.Duration {
+	lorawan := downlink.DownlinkOption.GetProtocolConfig().GetLorawan()
+	if lorawan == nil {
+		return 0
+	}
+	return computeTimeOnAir(lorawan, uint(len(downlink.Payload)))
+}
+
+// Priority classes of downlinks. Other classes can be configured by FPort.
+const (
+	ClassSystem   = "system"   // MAC-only downlinks (FPort 0)
+	ClassRealtime = "realtime" // Downlinks on FPorts that are not configured
+)
+
+// priorityClass returns the priority class of a downlink with the given FPort
+func (r *router) priorityClass(fPort uint8, hasFPort bool) string {
+	if !hasFPort {
+		return ClassRealtime
+	}
+	if fPort == 0 {
+		return ClassSystem
+	}
+	for class, fPorts := range r.classFPorts {
+		for _, classFPort := range fPorts {
+			if classFPort == int(fPort) {
+				return class
+			}
+		}
+	}
+	return ClassRealtime
+}
+
+// consumeClassQuota charges the airtime of the downlink to its priority class
+// on the gateway
+func (r *router) consumeClassQuota(gtw *gateway.Gateway, downlink *pb_broker.DownlinkMessage) error {
+	if len(r.classQuotas) == 0 {
+		return nil
+	}
+	airtime := downlinkTimeOnAir(downlink)
+	if airtime == 0 {
+		return nil
+	}
+	class := r.priorityClass(fPortFromPayload(downlink.Payload))
+	return gtw.ConsumeClassAirtime(class, airtime, r.classQuotas[class])
+}
+
+// refundClassQuota refunds the airtime that consumeClassQuota charged for the
+// downlink
+func (r *router) refundClassQuota(gtw *gateway.Gateway, downlink *pb_broker.DownlinkMessage) {
+	if len(r.classQuotas) == 0 {
+		return
+	}
+	airtime := downlinkTimeOnAir(downlink)
+	if airtime == 0 {
+		return
+	}
+	class := r.priorityClass(fPortFromPayload(downlink.Payload))
+	gtw.RefundClassAirtime(class, airtime, r.classQuotas[class])
+}
+
+// chargeDownlink charges the airtime of the downlink to the quota of the
+// network of the device and to the quota of its priority class on the gateway
+func (r *router) chargeDownlink(gtw *gateway.Gateway, downlink *pb_broker.DownlinkMessage) (NackReason, error) {
+	if err := r.consumeQuota(downlink); err != nil {
+		return NackQuotaExceeded, err
+	}
+	if err := r.consumeClassQuota(gtw, downlink); err != nil {
+		r.refundQuota(downlink)
+		return NackClassQuotaExceeded, err
+	}
+	return "", nil
+}
+
+// refundDownlink refunds the airtime that chargeDownlink charged for a downlink
+// that was not scheduled after all
+func (r *router) refundDownlink(gtw *gateway.Gateway, downlink *pb_broker.DownlinkMessage) {
+	r.refundQuota(downlink)
+	r.refundClassQuota(gtw, downlink)
+}
@@ -0,0 +1,135 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// recentUplinkTimeout is the time after which uplinks can no longer be used
+// for re-scoring downlink options
+const recentUplinkTimeout = 10 * time.Second
+
+// recentUplink is an uplink message as it was received by all gateways
+type recentUplink struct {
+	payload    []byte
+	receivedAt time.Time
+	gateways   map[string]*pb.UplinkMessage
+}
+
+// recentUplinks keeps track of the gateways that received the last uplink of
+// each device
+type recentUplinks struct {
+	sync.RWMutex
+	uplinks map[types.DevAddr]*recentUplink
+	sweptAt time.Time
+}
+
+func (u *recentUplinks) add(devAddr types.DevAddr, gatewayID string, uplink *pb.UplinkMessage) {
+	u.Lock()
+	defer u.Unlock()
+	if u.uplinks == nil {
+		u.uplinks = make(map[types.DevAddr]*recentUplink)
+	}
+	if time.Since(u.sweptAt) > recentUplinkTimeout {
+		for addr, recent := range u.uplinks {
+			if time.Since(recent.receivedAt) > recentUplinkTimeout {
+				delete(u.uplinks, addr)
+			}
+		}
+		u.sweptAt = time.Now()
+	}
+	recent, ok := u.uplinks[devAddr]
+	if !ok || !bytes.Equal(recent.payload, uplink.Payload) {
+		recent = &recentUplink{
+			payload:    uplink.Payload,
+			receivedAt: time.Now(),
+			gateways:   make(map[string]*pb.UplinkMessage),
+		}
+		u.uplinks[devAddr] = recent
+	}
+	recent.gateways[gatewayID] = uplink
+}
+
+func (u *recentUplinks) get(devAddr types.DevAddr) map[string]*pb.UplinkMessage {
+	u.RLock()
+	defer u.RUnlock()
+	recent, ok := u.uplinks[devAddr]
+	if !ok || time.Since(recent.receivedAt) > recentUplinkTimeout {
+		return nil
+	}
+	gateways := make(map[string]*pb.UplinkMessage, len(recent.gateways))
+	for gatewayID, uplink := range recent.gateways {
+		gateways[gatewayID] = uplink
+	}
+	return gateways
+}
+
+// sameWindow returns true if the options use the same frequency and data rate
+func sameWindow(a, b *pb_broker.DownlinkOption) bool {
+	if a.GatewayConfig.Frequency != b.GatewayConfig.Frequency {
+		return false
+	}
+	aLorawan, bLorawan := a.GetProtocolConfig().GetLorawan(), b.GetProtocolConfig().GetLorawan()
+	if aLorawan == nil || bLorawan == nil {
+		return false
+	}
+	return aLorawan.DataRate == bLorawan.DataRate && aLorawan.BitRate == bLorawan.BitRate
+}
+
+// rescoreDownlink computes the downlink options of all gateways that received
+// the last uplink of the device and that are currently available for downlink.
+// It returns the best option in the same window as the option of the downlink,
+// or nil if there is no option with a better score than that option.
+func (r *router) rescoreDownlink(downlink *pb_broker.DownlinkMessage) *pb_broker.DownlinkOption {
+	// Join-accepts have no DevAddr, so only data downlinks are rescored. Their
+	// options are therefore never built for an activation.
+	devAddr, ok := devAddrFromPayload(downlink.Payload)
+	if !ok {
+		return nil
+	}
+	uplinks := r.recentUplinks.get(devAddr)
+	if _, ok := uplinks[downlink.DownlinkOption.GatewayId]; !ok {
+		return nil // The option was not built from the last uplink
+	}
+
+	// The options are only scored here; only the better option is booked in the
+	// schedule of its gateway
+	var current, best *pb_broker.DownlinkOption
+	var bestGateway *gateway.Gateway
+	for gatewayID, uplink := range uplinks {
+		gtw := r.getGateway(gatewayID)
+		if !gtw.Schedule.IsActive() {
+			continue
+		}
+		_, _, options, _ := r.scoreDownlinkOptions(uplink, false, gtw, false)
+		for _, option := range options {
+			if !sameWindow(option, downlink.DownlinkOption) {
+				continue
+			}
+			if gatewayID == downlink.DownlinkOption.GatewayId {
+				current = option
+			}
+			if best == nil || option.Score < best.Score {
+				best, bestGateway = option, gtw
+			}
+		}
+	}
+
+	if best == nil || best.GatewayId == downlink.DownlinkOption.GatewayId {
+		return nil
+	}
+	if current != nil && current.Score <= best.Score {
+		return nil
+	}
+	r.bookDownlinkOption(bestGateway, best)
+	return best
+}
@@ -4,6 +4,9 @@
 package router
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,9 +16,13 @@ import (
 	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
 	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
 	pb_monitor "github.com/TheThingsNetwork/ttn/api/monitor"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/band"
 	"github.com/TheThingsNetwork/ttn/core/component"
 	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/spf13/viper"
 	"golang.org/x/net/context"
 )
 
@@ -28,12 +35,28 @@ type Router interface {
 	HandleGatewayStatus(gatewayID string, status *pb_gateway.Status) error
 	// Handle an uplink message from a gateway
 	HandleUplink(gatewayID string, uplink *pb.UplinkMessage) error
-	// Handle a downlink message
-	HandleDownlink(message *pb_broker.DownlinkMessage) error
+	// Handle a downlink message and return the result of scheduling it
+	HandleDownlink(message *pb_broker.DownlinkMessage) (*DownlinkResult, error)
+	// Handle a downlink message that is scheduled in multiple options of the same gateway
+	HandleDownlinkAttempts(messages ...*pb_broker.DownlinkMessage) error
+	// Handle downlink messages that are scheduled as a burst with a fixed interval
+	HandleDownlinkBurst(interval time.Duration, messages ...*pb_broker.DownlinkMessage) error
+	// Handle the acknowledgement of a downlink transmission by a gateway
+	HandleTxAck(gatewayID string, identifier string, err error) error
 	// Subscribe to downlink messages
 	SubscribeDownlink(gatewayID string, subscriptionID string) (<-chan *pb.DownlinkMessage, error)
 	// Unsubscribe from downlink messages
 	UnsubscribeDownlink(gatewayID string, subscriptionID string) error
+	// Get the number of downlink subscribers of gateways that have downlink subscriptions
+	Subscriptions() map[string]int
+	// Enable or disable the safe mode in which all downlinks are rejected
+	SetDownlinksDisabled(downlinksDisabled bool)
+	// Aggregate the airtime of all gateways between from and to per sub-band
+	DutyCycleReport(from, to time.Time) *DutyCycleReport
+	// Get the frequency plans, modulations and features that are supported
+	Capabilities() *pb.Capabilities
+	// Cancel all downlink of a gateway that was not sent yet
+	ClearSchedule(gatewayID string) (cleared int, err error)
 	// Handle a device activation
 	HandleActivation(gatewayID string, activation *pb.DeviceActivationRequest) (*pb.DeviceActivationResponse, error)
 
@@ -48,12 +71,138 @@ type broker struct {
 	downlink    chan *pb_broker.DownlinkMessage
 }
 
-// NewRouter creates a new Router
-func NewRouter() Router {
-	return &router{
-		gateways: make(map[string]*gateway.Gateway),
-		brokers:  make(map[string]*broker),
+// sortMode returns the order of downlink options that the broker requested
+func (b *broker) sortMode() SortMode {
+	return SortMode(b.association.DownlinkOptionsSort())
+}
+
+// NewRouter creates a new Router. It returns an error if the configuration of
+// the router is invalid.
+func NewRouter() (Router, error) {
+	var config configParser
+	r := &router{
+		gateways:       make(map[string]*gateway.Gateway),
+		brokers:        make(map[string]*broker),
+		rxOnlyGateways: make(map[string]bool),
+
+		stickiness: uint32(viper.GetInt("router.downlink-stickiness")),
+		minTXPower: int32(viper.GetInt("router.min-tx-power")),
+		subBands:   config.intValues("router.sub-bands"),
+
+		powerErrorThreshold: viper.GetInt("router.tx-power-error-threshold"),
+
+		rx1DROffsets:         config.intValues("router.rx1-dr-offset"),
+		txPowerIndices:       config.intValues("router.tx-power-index"),
+		rx2Frequencies:       config.intValues("router.rx2-fallback-frequencies"),
+		forbiddenFrequencies: config.intValues("router.forbidden-frequencies"),
+		timestampPrecisions:  config.intValues("router.timestamp-precision"),
+
+		maxScore:      uint32(viper.GetInt("router.max-acceptable-score")),
+		airtimeQuota:  viper.GetDuration("router.network-airtime-quota"),
+		switchGuard:   viper.GetDuration("router.rx-tx-switch-guard"),
+		txAckBonus:    uint32(viper.GetInt("router.txack-bonus")),
+		rx2PowerFloor: int32(viper.GetInt("router.rx2-power-floor")),
+		snrDominance:  viper.GetFloat64("router.snr-dominance"),
+		rescore:       viper.GetBool("router.rescore-downlink"),
+		guardFactor:   viper.GetFloat64("router.jitter-guard-factor"),
+		traceFrames:   viper.GetBool("router.trace-frames"),
+		alwaysRX2:     viper.GetBool("router.always-rx2"),
+
+		downlinkAttempts: viper.GetBool("router.downlink-attempts"),
+
+		gpsLostSuspendsClassB: viper.GetBool("router.gps-lost-suspends-class-b"),
+
+		dutyCycleGroupConfig: config.stringValues("router.duty-cycle-groups"),
+
+		classQuotas: config.fractions("router.priority-class-quotas"),
+		classFPorts: config.intValues("router.priority-class-fports"),
+	}
+	if config.err != nil {
+		return nil, config.err
+	}
+	r.safeMode.set(viper.GetBool("router.downlinks-disabled"))
+	for _, gatewayID := range viper.GetStringSlice("router.rx-only-gateways") {
+		r.rxOnlyGateways[gatewayID] = true
+	}
+	frequencyPlans, err := loadFrequencyPlans(viper.GetStringSlice("router.frequency-plans"))
+	if err != nil {
+		return nil, err
+	}
+	r.frequencyPlans = frequencyPlans
+	if err = r.validateFrequencyPlans(); err != nil {
+		return nil, err
+	}
+	if filename := viper.GetString("router.gateway-attributes"); filename != "" {
+		if r.gatewayAttributes, err = gateway.ReadAttributes(filename); err != nil {
+			return nil, err
+		}
+		if err = validateDutyCycleOverrides(r.gatewayAttributes); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// configParser parses lists of key=value pairs from the configuration. The
+// key is for example a region, a gateway ID or a platform. It keeps the first
+// error, so that the router can be configured before checking for errors.
+type configParser struct {
+	err error
+}
+
+func (p *configParser) fail(key, pair, reason string) {
+	if p.err == nil {
+		p.err = errors.NewErrInvalidArgument(key, fmt.Sprintf("%s: %s", pair, reason))
+	}
+}
+
+// stringValues parses a list of key=value pairs
+func (p *configParser) stringValues(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range viper.GetStringSlice(key) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			p.fail(key, pair, "not a key=value pair")
+			continue
+		}
+		values[parts[0]] = parts[1]
+	}
+	return values
+}
+
+// fractions parses a list of key=fraction pairs, for example class=share.
+// Fractions must be in (0, 1].
+func (p *configParser) fractions(key string) map[string]float64 {
+	fractions := make(map[string]float64)
+	for k, value := range p.stringValues(key) {
+		fraction, err := strconv.ParseFloat(value, 64)
+		if err != nil || fraction <= 0 || fraction > 1 {
+			p.fail(key, k+"="+value, "not a fraction between 0 and 1")
+			continue
+		}
+		fractions[k] = fraction
+	}
+	return fractions
+}
+
+// intValues parses a list of key=integer pairs. A key can occur more than
+// once, for example region=subband.
+func (p *configParser) intValues(key string) map[string][]int {
+	values := make(map[string][]int)
+	for _, pair := range viper.GetStringSlice(key) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			p.fail(key, pair, "not a key=value pair")
+			continue
+		}
+		value, err := strconv.Atoi(parts[1])
+		if err != nil {
+			p.fail(key, pair, "not an integer")
+			continue
+		}
+		values[parts[0]] = append(values[parts[0]], value)
 	}
+	return values
 }
 
 type router struct {
@@ -63,6 +212,117 @@ type router struct {
 	brokers      map[string]*broker
 	brokersLock  sync.RWMutex
 	status       *status
+
+	// stickiness is the score bonus for the gateway that was used for the
+	// previous downlink to a device
+	stickiness   uint32
+	lastDownlink lastDownlinkGateways
+
+	// minTXPower is the default minimum conducted TX power of gateways
+	minTXPower int32
+
+	// rxOnlyGateways contains the IDs of gateways that can not transmit
+	rxOnlyGateways map[string]bool
+
+	// powerErrorThreshold is the number of TX_POWER errors at the same TX
+	// power after which the maximum TX power of a gateway is lowered
+	powerErrorThreshold int
+
+	// gatewayAttributes are the configured attributes of gateways
+	gatewayAttributes map[string]gateway.Attributes
+
+	// frequencyPlans overrides the built-in frequency plans of regions. The
+	// sub-bands and other region values are applied on top of it.
+	frequencyPlans map[string]band.FrequencyPlan
+
+	// subBands contains the active sub-bands for regions that have sub-bands
+	subBands map[string][]int
+
+	// rx1DROffsets contains the RX1 data rate offset for regions
+	rx1DROffsets map[string][]int
+
+	// txPowerIndices contains the default TX power index of downlink for
+	// regions
+	txPowerIndices map[string][]int
+
+	// rx2Frequencies contains the ordered RX2 frequencies that are used if the
+	// default RX2 frequency of a region is saturated
+	rx2Frequencies map[string][]int
+
+	// forbiddenFrequencies contains the frequencies on which transmissions are
+	// forbidden, in addition to the frequencies outside the sub-bands of the
+	// region
+	forbiddenFrequencies map[string][]int
+
+	// maxScore is the maximum score of downlink options; options with a higher
+	// score are dropped
+	maxScore uint32
+
+	// airtimeQuota is the maximum downlink airtime per hour of each network
+	airtimeQuota time.Duration
+	quota        gateway.AirtimeWindow
+
+	// dutyCycleGroupConfig contains the duty cycle group of gateways that
+	// share their duty cycle with other gateways
+	dutyCycleGroupConfig map[string]string
+	dutyCycleGroups      map[string]*gateway.DutyCycleGroup
+
+	// classQuotas contains the share of the downlink airtime of a gateway that
+	// each priority class may use; classFPorts contains the FPorts of the
+	// downlinks that belong to each priority class
+	classQuotas map[string]float64
+	classFPorts map[string][]int
+
+	// switchGuard is the time that gateways need to switch from RX to TX
+	switchGuard time.Duration
+
+	// txAckBonus is the maximum score bonus for gateways that recently
+	// acknowledged the transmission of their downlinks
+	txAckBonus uint32
+
+	// rx2PowerFloor is the minimum TX power (in dBm) of RX2, as it is sent to
+	// the gateway; it is limited by the maximum EIRP of the region and by the
+	// maximum TX power of the gateway
+	rx2PowerFloor int32
+
+	// snrDominance shifts the weight of the signal score between SNR (1) and
+	// RSSI (-1)
+	snrDominance float64
+
+	// rescore enables re-scoring the downlink options of all gateways that
+	// received an uplink at the time of the downlink
+	rescore       bool
+	recentUplinks recentUplinks
+
+	// guardFactor is the factor by which the guard between transmissions of
+	// a gateway scales with the measured precision of its timestamps
+	guardFactor float64
+
+	// timestampPrecisions contains the precision of the timestamps (in µs)
+	// of gateways per platform
+	timestampPrecisions map[string][]int
+
+	// alwaysRX2 also sends downlinks in RX2 when they are sent in RX1
+	alwaysRX2  bool
+	rx2Options rx2Options
+
+	// optionTraces keeps the window of downlink options for tracing
+	optionTraces optionTraces
+
+	// downlinkAttempts schedules RX2 as a second attempt for downlinks in RX1,
+	// which is cancelled when the gateway acknowledges the transmission in RX1
+	downlinkAttempts bool
+
+	// traceFrames logs the payload of all downlink frames that are sent to
+	// gateways. This should not be enabled in production.
+	traceFrames bool
+
+	// safeMode can be activated to reject all downlinks
+	safeMode safeMode
+
+	// gpsLostSuspendsClassB suspends Class B downlink through gateways that
+	// lost their GPS lock
+	gpsLostSuspendsClassB bool
 }
 
 func (r *router) tickGateways() {
@@ -91,6 +351,9 @@ func (r *router) Init(c *component.Component) error {
 			r.tickGateways()
 		}
 	}()
+	if r.safeMode.active() {
+		r.Ctx.Warn("Safe mode active: all downlinks are disabled")
+	}
 	r.Component.SetStatus(component.StatusHealthy)
 	return nil
 }
@@ -120,6 +383,26 @@ func (r *router) getGateway(id string) *gateway.Gateway {
 	gtw, ok = r.gateways[id]
 	if !ok {
 		gtw = gateway.NewGateway(r.Ctx, id)
+		gtw.MinTXPower = r.minTXPower
+		gtw.RXOnly = r.rxOnlyGateways[id]
+		gtw.PowerErrorThreshold = r.powerErrorThreshold
+		if r.Tracing() {
+			gtw.Tracer = r.Tracer(tracerName)
+		}
+		if attributes, ok := r.gatewayAttributes[id]; ok {
+			gtw.SetAttributes(attributes)
+		}
+		gtw.Schedule.SetGuardFactor(r.guardFactor)
+
+		if group, ok := r.dutyCycleGroupConfig[id]; ok {
+			if r.dutyCycleGroups == nil {
+				r.dutyCycleGroups = make(map[string]*gateway.DutyCycleGroup)
+			}
+			if _, ok := r.dutyCycleGroups[group]; !ok {
+				r.dutyCycleGroups[group] = gateway.NewDutyCycleGroup(group)
+			}
+			r.dutyCycleGroups[group].Add(gtw)
+		}
 
 		if r.Component.Monitors != nil {
 			gtw.Monitors = make(map[string]pb_monitor.GatewayClient)
@@ -176,7 +459,18 @@ func (r *router) getBroker(brokerAnnouncement *pb_discovery.Announcement) (*brok
 				case message := <-brk.uplink:
 					association.Send(message)
 				case message := <-downlink:
-					go r.HandleDownlink(message)
+					if err := pb_lorawan.ValidateDownlinkFrame(message.Payload); err != nil {
+						r.Ctx.WithError(err).Warn("Received invalid downlink from broker")
+						continue
+					}
+					go func(message *pb_broker.DownlinkMessage) {
+						res, err := r.HandleDownlink(message)
+						if err != nil {
+							r.Ctx.WithError(err).WithField("Reason", res.NackReason).Warn("Could not schedule downlink from broker")
+						}
+						// Let the broker know if the downlink is sent, and in which window
+						brk.uplink <- &pb_broker.UplinkMessage{DownlinkResult: res.brokerResult(message)}
+					}(message)
 				}
 			}
 		}()
@@ -3,8 +3,43 @@
 
 package router
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/smartystreets/assertions"
+	"github.com/spf13/viper"
+)
 
 func TestRouterIntegration(t *testing.T) {
 
 }
+
+func TestConfigParser(t *testing.T) {
+	a := New(t)
+
+	viper.Set("test.values", []string{"US_902_928=2", "US_902_928=3", "AU_915_928=1"})
+	viper.Set("test.fractions", []string{"bulk=0.002"})
+	defer viper.Set("test.values", []string{})
+	defer viper.Set("test.fractions", []string{})
+
+	var config configParser
+	a.So(config.intValues("test.values"), ShouldResemble, map[string][]int{"US_902_928": {2, 3}, "AU_915_928": {1}})
+	a.So(config.stringValues("test.values"), ShouldResemble, map[string]string{"US_902_928": "3", "AU_915_928": "1"})
+	a.So(config.fractions("test.fractions"), ShouldResemble, map[string]float64{"bulk": 0.002})
+	a.So(config.err, ShouldBeNil)
+
+	for _, invalid := range []string{"US_902_928", "=2", "US_902_928=two"} {
+		viper.Set("test.values", []string{invalid})
+		config = configParser{}
+		config.intValues("test.values")
+		a.So(errors.GetErrType(config.err), ShouldEqual, errors.InvalidArgument)
+	}
+
+	for _, invalid := range []string{"bulk=0", "bulk=1.5", "bulk=half"} {
+		viper.Set("test.fractions", []string{invalid})
+		config = configParser{}
+		config.fractions("test.fractions")
+		a.So(errors.GetErrType(config.err), ShouldEqual, errors.InvalidArgument)
+	}
+}
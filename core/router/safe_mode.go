@@ -0,0 +1,52 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"sync/atomic"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ErrDownlinksDisabled is returned for all downlink while the safe mode is active
+var ErrDownlinksDisabled = errors.New("Downlinks are disabled")
+
+// safeMode indicates whether downlinks are disabled
+type safeMode struct {
+	downlinksDisabled int32
+}
+
+func (s *safeMode) set(downlinksDisabled bool) (changed bool) {
+	var value int32
+	if downlinksDisabled {
+		value = 1
+	}
+	return atomic.SwapInt32(&s.downlinksDisabled, value) != value
+}
+
+func (s *safeMode) active() bool {
+	return atomic.LoadInt32(&s.downlinksDisabled) == 1
+}
+
+// SetDownlinksDisabled enables or disables the safe mode in which all
+// downlinks are rejected
+func (r *router) SetDownlinksDisabled(downlinksDisabled bool) {
+	if !r.safeMode.set(downlinksDisabled) {
+		return
+	}
+	if downlinksDisabled {
+		r.Ctx.Warn("Safe mode active: all downlinks are disabled")
+	} else {
+		r.Ctx.Info("Safe mode inactive: downlinks are enabled")
+	}
+}
+
+// checkSafeMode returns ErrDownlinksDisabled if the safe mode is active
+func (r *router) checkSafeMode() error {
+	if r.safeMode.active() {
+		r.Ctx.Warn("Safe mode active: rejected downlink")
+		return ErrDownlinksDisabled
+	}
+	return nil
+}
@@ -106,6 +106,20 @@ func (r *routerRPC) getGatewayStatus(md metadata.MD) (ch chan *pb_gateway.Status
 	return
 }
 
+func (r *routerRPC) getTxAck(md metadata.MD) (ch chan *pb.TxAcknowledgment, err error) {
+	gateway, err := r.gatewayFromMetadata(md)
+	if err != nil {
+		return nil, err
+	}
+	ch = make(chan *pb.TxAcknowledgment)
+	go func() {
+		for ack := range ch {
+			r.router.HandleTxAck(gateway.ID, ack.Identifier, ack.Err())
+		}
+	}()
+	return
+}
+
 func (r *routerRPC) getDownlink(md metadata.MD) (ch <-chan *pb.DownlinkMessage, cancel func(), err error) {
 	gateway, err := r.gatewayFromMetadata(md)
 	if err != nil {
@@ -145,6 +159,7 @@ func (r *router) RegisterRPC(s *grpc.Server) {
 	server.UplinkChanFunc = server.getUplink
 	server.DownlinkChanFunc = server.getDownlink
 	server.GatewayStatusChanFunc = server.getGatewayStatus
+	server.TxAckChanFunc = server.getTxAck
 
 	// TODO: Monitor actual rates and configure sensible limits
 	//
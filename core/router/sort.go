@@ -0,0 +1,71 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"sort"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+)
+
+// SortMode is the order in which downlink options are sent to the broker
+type SortMode string
+
+// Sort modes for downlink options
+const (
+	SortNone    SortMode = ""        // RX2 followed by RX1
+	SortScore   SortMode = "score"   // Lowest (best) score first
+	SortWindow  SortMode = "window"  // Earliest window first
+	SortAirtime SortMode = "airtime" // Lowest airtime first
+)
+
+type downlinkOptionSorter struct {
+	options []*pb_broker.DownlinkOption
+	less    func(a, b *pb_broker.DownlinkOption) bool
+}
+
+func (s downlinkOptionSorter) Len() int           { return len(s.options) }
+func (s downlinkOptionSorter) Less(i, j int) bool { return s.less(s.options[i], s.options[j]) }
+func (s downlinkOptionSorter) Swap(i, j int)      { s.options[i], s.options[j] = s.options[j], s.options[i] }
+
+func optionAirtime(option *pb_broker.DownlinkOption) time.Duration {
+	lorawan := option.GetProtocolConfig().GetLorawan()
+	if lorawan == nil {
+		return 0
+	}
+	return computeTimeOnAir(lorawan, 51+13)
+}
+
+// SortDownlinkOptions sorts the downlink options in place. Options that are
+// equal in the sort mode keep their order.
+func SortDownlinkOptions(options []*pb_broker.DownlinkOption, mode SortMode) {
+	var less func(a, b *pb_broker.DownlinkOption) bool
+	switch mode {
+	case SortScore:
+		less = func(a, b *pb_broker.DownlinkOption) bool { return a.Score < b.Score }
+	case SortWindow:
+		less = func(a, b *pb_broker.DownlinkOption) bool {
+			return a.GatewayConfig.Timestamp < b.GatewayConfig.Timestamp
+		}
+	case SortAirtime:
+		less = func(a, b *pb_broker.DownlinkOption) bool { return optionAirtime(a) < optionAirtime(b) }
+	default:
+		return
+	}
+	sort.Stable(downlinkOptionSorter{options, less})
+}
+
+// sortedDownlinkOptions returns a copy of the downlink options that is sorted
+// in the sort mode that a broker requested. The options are shared between
+// brokers, so they are not sorted in place.
+func sortedDownlinkOptions(options []*pb_broker.DownlinkOption, mode SortMode) []*pb_broker.DownlinkOption {
+	if mode == SortNone || len(options) < 2 {
+		return options
+	}
+	sorted := make([]*pb_broker.DownlinkOption, len(options))
+	copy(sorted, options)
+	SortDownlinkOptions(sorted, mode)
+	return sorted
+}
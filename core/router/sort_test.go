@@ -0,0 +1,62 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestSortDownlinkOptions(t *testing.T) {
+	a := New(t)
+
+	option := func(id string, score uint32, timestamp uint32, dataRate string) *pb_broker.DownlinkOption {
+		return &pb_broker.DownlinkOption{
+			Identifier: id,
+			Score:      score,
+			ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{
+				Modulation: pb_lorawan.Modulation_LORA,
+				DataRate:   dataRate,
+				CodingRate: "4/5",
+			}}},
+			GatewayConfig: &pb_gateway.TxConfiguration{Timestamp: timestamp},
+		}
+	}
+
+	options := func() []*pb_broker.DownlinkOption {
+		return []*pb_broker.DownlinkOption{
+			option("a", 30, 2000000, "SF9BW125"),
+			option("b", 10, 1000000, "SF12BW125"),
+			option("c", 20, 3000000, "SF7BW125"),
+		}
+	}
+
+	identifiers := func(options []*pb_broker.DownlinkOption) (ids []string) {
+		for _, option := range options {
+			ids = append(ids, option.Identifier)
+		}
+		return
+	}
+
+	for mode, expected := range map[SortMode][]string{
+		SortNone:    {"a", "b", "c"},
+		SortScore:   {"b", "c", "a"},
+		SortWindow:  {"b", "a", "c"},
+		SortAirtime: {"c", "a", "b"},
+	} {
+		sorted := options()
+		SortDownlinkOptions(sorted, mode)
+		a.So(identifiers(sorted), ShouldResemble, expected)
+
+		// The options that are shared between brokers keep their order
+		shared := options()
+		a.So(identifiers(sortedDownlinkOptions(shared, mode)), ShouldResemble, expected)
+		a.So(identifiers(shared), ShouldResemble, []string{"a", "b", "c"})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"sync"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+)
+
+// lastDownlinkTimeout is the time after which the gateway that was used for
+// the last downlink to a device is forgotten
+const lastDownlinkTimeout = 24 * time.Hour
+
+// lastDownlinkGateway is the gateway that was used for a downlink
+type lastDownlinkGateway struct {
+	gatewayID string
+	sentAt    time.Time
+}
+
+// lastDownlinkGateways keeps track of the gateway that was used for the last
+// downlink to each device
+type lastDownlinkGateways struct {
+	sync.RWMutex
+	gateways map[types.DevAddr]lastDownlinkGateway
+	sweptAt  time.Time
+}
+
+func (l *lastDownlinkGateways) set(devAddr types.DevAddr, gatewayID string) {
+	l.Lock()
+	defer l.Unlock()
+	if l.gateways == nil {
+		l.gateways = make(map[types.DevAddr]lastDownlinkGateway)
+	}
+	if time.Since(l.sweptAt) > lastDownlinkTimeout {
+		for addr, last := range l.gateways {
+			if time.Since(last.sentAt) > lastDownlinkTimeout {
+				delete(l.gateways, addr)
+			}
+		}
+		l.sweptAt = time.Now()
+	}
+	l.gateways[devAddr] = lastDownlinkGateway{gatewayID: gatewayID, sentAt: time.Now()}
+}
+
+func (l *lastDownlinkGateways) get(devAddr types.DevAddr) (gatewayID string, ok bool) {
+	l.RLock()
+	defer l.RUnlock()
+	last, ok := l.gateways[devAddr]
+	if !ok || time.Since(last.sentAt) > lastDownlinkTimeout {
+		return "", false
+	}
+	return last.gatewayID, true
+}
+
+// devAddrFromPayload returns the DevAddr of a LoRaWAN data message
+func devAddrFromPayload(payload []byte) (devAddr types.DevAddr, ok bool) {
+	var phyPayload lorawan.PHYPayload
+	if err := phyPayload.UnmarshalBinary(payload); err != nil {
+		return
+	}
+	macPayload, ok := phyPayload.MACPayload.(*lorawan.MACPayload)
+	if !ok {
+		return
+	}
+	return types.DevAddr(macPayload.FHDR.DevAddr), true
+}
+
+// applyStickiness lowers the score of the options if the gateway was also used
+// for the previous downlink to the device. This makes sure that we keep using
+// the same gateway if the scores of multiple gateways are (almost) equal.
+func (r *router) applyStickiness(uplink *pb.UplinkMessage, gatewayID string, options []*pb_broker.DownlinkOption) {
+	if r.stickiness == 0 {
+		return
+	}
+	devAddr, ok := devAddrFromPayload(uplink.Payload)
+	if !ok {
+		return
+	}
+	if lastGatewayID, ok := r.lastDownlink.get(devAddr); !ok || lastGatewayID != gatewayID {
+		return
+	}
+	lowerScores(options, r.stickiness)
+}
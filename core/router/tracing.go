@@ -0,0 +1,71 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"sync"
+	"time"
+)
+
+// tracerName is the name of the OpenTelemetry tracer of the router
+const tracerName = "github.com/TheThingsNetwork/ttn/core/router"
+
+// optionTraceTimeout is the time after which downlink options are no longer
+// traced
+const optionTraceTimeout = 10 * time.Second
+
+// Reasons why downlink options are dropped, in addition to the errors of
+// building them
+const (
+	dropNotAllowed = "not allowed"
+	dropMaxScore   = "above the maximum score"
+)
+
+// optionTrace is what is known about a downlink option when it is built. It is
+// added to the span of the scheduling decision if the option is used. The drops
+// are the reasons why the other options for the same uplink were dropped.
+type optionTrace struct {
+	window    string
+	drops     []string
+	createdAt time.Time
+}
+
+// optionTraces keeps track of the downlink options that were built, until the
+// broker picks one of them. The window is also used in the result of the
+// downlink.
+type optionTraces struct {
+	sync.Mutex
+	options map[string]optionTrace
+	sweptAt time.Time
+}
+
+func (o *optionTraces) add(identifier string, window string, drops []string) {
+	o.Lock()
+	defer o.Unlock()
+	now := time.Now()
+	if o.options == nil {
+		o.options = make(map[string]optionTrace)
+	}
+	if now.Sub(o.sweptAt) > optionTraceTimeout {
+		for identifier, option := range o.options {
+			if now.Sub(option.createdAt) > optionTraceTimeout {
+				delete(o.options, identifier)
+			}
+		}
+		o.sweptAt = now
+	}
+	o.options[identifier] = optionTrace{window: window, drops: drops, createdAt: now}
+}
+
+// get returns and removes the trace of the downlink option
+func (o *optionTraces) get(identifier string) (optionTrace, bool) {
+	o.Lock()
+	defer o.Unlock()
+	option, ok := o.options[identifier]
+	if !ok || time.Since(option.createdAt) > optionTraceTimeout {
+		return optionTrace{}, false
+	}
+	delete(o.options, identifier)
+	return option, true
+}
@@ -0,0 +1,22 @@
+// Copyright © 2016 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+)
+
+// applyTxAckBonus lowers the score of the options in proportion to the
+// fraction of recent downlinks that the gateway acknowledged as transmitted.
+func (r *router) applyTxAckBonus(gateway *gateway.Gateway, options []*pb_broker.DownlinkOption) {
+	if r.txAckBonus == 0 {
+		return
+	}
+	rate, ok := gateway.TxAckSuccessRate()
+	if !ok {
+		return
+	}
+	lowerScores(options, uint32(rate*float64(r.txAckBonus)))
+}
@@ -87,6 +87,10 @@ func (r *router) HandleUplink(gatewayID string, uplink *pb.UplinkMessage) (err e
 		return err
 	}
 
+	if r.rescore {
+		r.recentUplinks.add(devAddr, gatewayID, uplink)
+	}
+
 	var downlinkOptions []*pb_broker.DownlinkOption
 	if gateway.Schedule.IsActive() {
 		downlinkOptions = r.buildDownlinkOptions(uplink, false, gateway)
@@ -117,7 +121,7 @@ func (r *router) HandleUplink(gatewayID string, uplink *pb.UplinkMessage) (err e
 			Payload:          uplink.Payload,
 			ProtocolMetadata: uplink.ProtocolMetadata,
 			GatewayMetadata:  uplink.GatewayMetadata,
-			DownlinkOptions:  downlinkOptions,
+			DownlinkOptions:  sortedDownlinkOptions(downlinkOptions, broker.sortMode()),
 		}
 	}
 
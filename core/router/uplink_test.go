@@ -4,13 +4,17 @@
 package router
 
 import (
+	"errors"
 	"testing"
 
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
 	"github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/api/discovery/discoverytest"
 	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
 	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/component"
 	"github.com/TheThingsNetwork/ttn/core/router/gateway"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
@@ -80,3 +84,120 @@ func TestHandleUplink(t *testing.T) {
 
 	// TODO: Integration test that checks broker forward
 }
+
+func TestHandleUplinkBrokerPrefixRouting(t *testing.T) {
+	a := New(t)
+
+	disc := discoverytest.NewMemoryClient(&discovery.Announcement{ServiceName: "router", Id: "router"})
+	prefixA, _ := types.ParseDevAddrPrefix("01000000/8")
+	prefixB, _ := types.ParseDevAddrPrefix("26000000/7")
+	disc.AddBroker("broker-a", prefixA)
+	disc.AddBroker("broker-b", prefixB)
+
+	brokerA := &broker{uplink: make(chan *pb_broker.UplinkMessage, 1)}
+	brokerB := &broker{uplink: make(chan *pb_broker.UplinkMessage, 1)}
+
+	r := &router{
+		Component: &component.Component{
+			Discovery: disc,
+			Ctx:       GetLogger(t, "TestHandleUplinkBrokerPrefixRouting"),
+		},
+		gateways: map[string]*gateway.Gateway{},
+		brokers: map[string]*broker{
+			"broker-a": brokerA,
+			"broker-b": brokerB,
+		},
+	}
+	r.InitStatus()
+
+	// DevAddr 01020304 is only forwarded to broker A
+	err := r.HandleUplink("eui-0102030405060708", newReferenceUplink())
+	a.So(err, ShouldBeNil)
+	a.So(brokerA.uplink, ShouldHaveLength, 1)
+	a.So(brokerB.uplink, ShouldBeEmpty)
+
+	// Failures of the discovery server are returned
+	disc.SetError(errors.New("discovery unavailable"))
+	err = r.HandleUplink("eui-0102030405060708", newReferenceUplink())
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestHandleUplinkRXOnlyGateway(t *testing.T) {
+	a := New(t)
+
+	disc := discoverytest.NewMemoryClient(&discovery.Announcement{ServiceName: "router", Id: "router"})
+	prefix, _ := types.ParseDevAddrPrefix("01000000/8")
+	disc.AddBroker("broker", prefix)
+
+	brk := &broker{uplink: make(chan *pb_broker.UplinkMessage, 2)}
+
+	rxOnlyID, txID := "eui-0102030405060708", "eui-0807060504030201"
+
+	r := &router{
+		Component: &component.Component{
+			Discovery: disc,
+			Ctx:       GetLogger(t, "TestHandleUplinkRXOnlyGateway"),
+		},
+		gateways:       map[string]*gateway.Gateway{},
+		brokers:        map[string]*broker{"broker": brk},
+		rxOnlyGateways: map[string]bool{rxOnlyID: true},
+		rescore:        true,
+	}
+	r.InitStatus()
+
+	for _, gtwID := range []string{rxOnlyID, txID} {
+		r.getGateway(gtwID).Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+		ch, err := r.SubscribeDownlink(gtwID, "")
+		a.So(err, ShouldBeNil)
+		go func() {
+			for range ch {
+			}
+		}()
+		defer r.UnsubscribeDownlink(gtwID, "")
+	}
+
+	// The RX-only gateway hears the uplink much better
+	rxOnlyUplink := newReferenceUplink()
+	rxOnlyUplink.GatewayMetadata.Rssi, rxOnlyUplink.GatewayMetadata.Snr = -20, 10
+	a.So(r.HandleUplink(rxOnlyID, rxOnlyUplink), ShouldBeNil)
+
+	txUplink := newReferenceUplink()
+	txUplink.GatewayMetadata.GatewayId = txID
+	txUplink.GatewayMetadata.Rssi, txUplink.GatewayMetadata.Snr = -110, -5
+	a.So(r.HandleUplink(txID, txUplink), ShouldBeNil)
+
+	// Both uplinks are forwarded, but only the TX-capable gateway has options
+	a.So(brk.uplink, ShouldHaveLength, 2)
+	var options []*pb_broker.DownlinkOption
+	for i := 0; i < 2; i++ {
+		uplink := <-brk.uplink
+		a.So(uplink.GatewayMetadata, ShouldNotBeNil)
+		options = append(options, uplink.DownlinkOptions...)
+	}
+	a.So(options, ShouldNotBeEmpty)
+	for _, option := range options {
+		a.So(option.GatewayId, ShouldEqual, txID)
+	}
+
+	// Re-scoring does not move the downlink to the RX-only gateway
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataDown,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{
+				DevAddr: lorawan.DevAddr([4]byte{1, 2, 3, 4}),
+			},
+		},
+	}
+	payload, _ := phy.MarshalBinary()
+	res, err := r.HandleDownlink(&pb_broker.DownlinkMessage{
+		Payload:        payload,
+		DownlinkOption: options[0],
+	})
+	a.So(err, ShouldBeNil)
+	a.So(res.Accepted, ShouldBeTrue)
+	a.So(r.getGateway(txID).Schedule.Conflicts(options[0].GatewayConfig.Timestamp, 1), ShouldBeGreaterThanOrEqualTo, 100)
+	a.So(r.getGateway(rxOnlyID).Schedule.Conflicts(options[0].GatewayConfig.Timestamp, 1), ShouldEqual, 0)
+}
@@ -82,6 +82,7 @@ var gatewaysStatusCmd = &cobra.Command{
 		}())
 		printKV("Rx", fmt.Sprintf("(in: %d; ok: %d)", resp.Status.RxIn, resp.Status.RxOk))
 		printKV("Tx", fmt.Sprintf("(in: %d; ok: %d)", resp.Status.TxIn, resp.Status.TxOk))
+		printKV("Lead time", fmt.Sprintf("(current: %s; max: %s)", time.Duration(resp.LeadTime), time.Duration(resp.MaxLeadTime)))
 		fmt.Println()
 	},
 }